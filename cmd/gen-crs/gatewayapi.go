@@ -0,0 +1,311 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
+
+	"github.com/kuadrant/gateway-api-state-metrics/pkg/crs"
+)
+
+const (
+	gatewayAPIGroup             = "gateway.networking.k8s.io"
+	gatewayAPIExperimentalGroup = "gateway.networking.x-k8s.io"
+)
+
+// maxListeners is the number of listeners of a Gateway or ListenerSet with
+// metrics for their TLS config and status. The CRDs allow 64, but each
+// listener needs metrics of its own in the config, see crs.ForEachIndex.
+const maxListeners = 8
+
+// gatewayAPIKinds are the Gateway API kinds, in the order they are written to
+// the config. Experimental kinds are only configured for the experimental
+// channel, and only if they are in the target release.
+var gatewayAPIKinds = []struct {
+	group        string
+	kind         string
+	experimental bool
+	resource     func(gvk customresourcestate.GroupVersionKind, opts options) customresourcestate.Resource
+}{
+	{gatewayAPIGroup, "Gateway", false, gateway},
+	{gatewayAPIGroup, "GatewayClass", false, gatewayClass},
+	{gatewayAPIGroup, "HTTPRoute", false, route(true, httpRouteRules)},
+	{gatewayAPIGroup, "GRPCRoute", false, route(true, grpcRouteRules)},
+	{gatewayAPIGroup, "TCPRoute", false, route(false, nil)},
+	{gatewayAPIGroup, "TLSRoute", false, route(true, nil)},
+	{gatewayAPIGroup, "UDPRoute", false, route(false, nil)},
+	{gatewayAPIGroup, "BackendTLSPolicy", false, backendTLSPolicy},
+	{gatewayAPIGroup, "ReferenceGrant", true, referenceGrant},
+	{gatewayAPIGroup, "BackendLBPolicy", true, backendTrafficPolicy},
+	{gatewayAPIExperimentalGroup, "XBackendTrafficPolicy", true, backendTrafficPolicy},
+	{gatewayAPIExperimentalGroup, "XListenerSet", true, listenerSet},
+}
+
+// options are the options of the generated config.
+type options struct {
+	// experimental adds the kinds and fields of the experimental channel.
+	experimental bool
+	// routeValues adds the values of route matches and filters, such as
+	// paths and header values, as labels.
+	routeValues bool
+}
+
+// gatewayAPIResources returns the configs of the Gateway API kinds for a
+// Gateway API release and options, in the order they are written to the
+// config.
+func gatewayAPIResources(release gatewayAPIRelease, opts options) ([]customresourcestate.Resource, error) {
+	var resources []customresourcestate.Resource
+	for _, k := range gatewayAPIKinds {
+		if k.experimental && !opts.experimental {
+			continue
+		}
+		version, ok := release.version(k.kind)
+		if !ok {
+			if k.experimental {
+				continue
+			}
+			return nil, fmt.Errorf("%s is not in Gateway API %s", k.kind, release.name)
+		}
+		gvk := customresourcestate.GroupVersionKind{Group: k.group, Version: version, Kind: k.kind}
+		resources = append(resources, k.resource(gvk, opts))
+	}
+	return resources, nil
+}
+
+// gateway returns the config of Gateway. The experimental channel adds the
+// metrics of the experimental fields of Gateway.
+func gateway(gvk customresourcestate.GroupVersionKind, opts options) customresourcestate.Resource {
+	var experimentalMetrics []customresourcestate.Generator
+	if opts.experimental {
+		experimentalMetrics = crs.Metrics(
+			crs.Info("infrastructure_info", "Gateway infrastructure parameters", crs.Path{"spec", "infrastructure"}, crs.Labels{
+				"parameters_ref_group": {"parametersRef", "group"},
+				"parameters_ref_kind":  {"parametersRef", "kind"},
+				"parameters_ref_name":  {"parametersRef", "name"},
+			}),
+			crs.Info("backend_tls_client_certificate_ref_info", "Client certificate the Gateway presents to backends", crs.Path{"spec", "backendTLS", "clientCertificateRef"}, crs.Labels{
+				"certificate_group":     {"group"},
+				"certificate_kind":      {"kind"},
+				"certificate_name":      {"name"},
+				"certificate_namespace": {"namespace"},
+			}),
+		)
+	}
+	return crs.Resource(prefix(gvk.Kind), gvk, true,
+		crs.Metrics(
+			crs.Info("info", "Gateway information", nil, crs.Labels{
+				"gatewayclass_name": {"spec", "gatewayClassName"},
+			}),
+		),
+		crs.MetadataMetrics(),
+		crs.Metrics(
+			crs.Info("listener_info", "Gateway listener information", crs.Path{"spec", "listeners"}, crs.Labels{
+				"listener_name":                  {"name"},
+				"port":                           {"port"},
+				"protocol":                       {"protocol"},
+				"hostname":                       {"hostname"},
+				"tls_mode":                       {"tls", "mode"},
+				"allowed_routes_namespaces_from": {"allowedRoutes", "namespaces", "from"},
+			}),
+		),
+		listenerTLS(),
+		crs.Metrics(
+			crs.StatusConditions(),
+			crs.StatusLastTransitionTime(),
+			crs.Gauge("status_listener_attached_routes", "Number of attached routes for a listener", crs.Path{"status", "listeners"}, crs.Labels{
+				"listener_name": {"name"},
+			}, crs.Path{"attachedRoutes"}),
+		),
+		crs.StatusObservedGeneration("Accepted", "Programmed"),
+		listenerStatus("Gateway"),
+		crs.Metrics(
+			crs.Info("status_address_info", "Gateway address types and values", crs.Path{"status", "addresses"}, crs.Labels{
+				"type":  {"type"},
+				"value": {"value"},
+			}),
+		),
+		experimentalMetrics,
+	)
+}
+
+// listenerTLS returns the listener_certificate_ref_info metric with the
+// certificates of each listener of a Gateway, and the listener_tls_option_info
+// metric with the keys of its TLS options. kube-state-metrics can only expose
+// the keys of a map, so the values of the options are left out.
+func listenerTLS() []customresourcestate.Generator {
+	certificateRefs := crs.ForEachIndex(crs.Path{"spec", "listeners"}, maxListeners, func(listener func(...string) crs.Path) customresourcestate.Generator {
+		m := crs.Info("listener_certificate_ref_info", "Certificates of the Gateway listeners", listener("tls", "certificateRefs"), crs.Labels{
+			"certificate_group":     {"group"},
+			"certificate_kind":      {"kind"},
+			"certificate_name":      {"name"},
+			"certificate_namespace": {"namespace"},
+		})
+		m.Labels.LabelsFromPath = crs.Labels{"listener_name": listener("name")}
+		return m
+	})
+	options := crs.ForEachIndex(crs.Path{"spec", "listeners"}, maxListeners, func(listener func(...string) crs.Path) customresourcestate.Generator {
+		m := crs.Info("listener_tls_option_info", "TLS options of the Gateway listeners", listener("tls", "options"), nil)
+		m.Each.Info.LabelFromKey = "option"
+		m.Labels.LabelsFromPath = crs.Labels{"listener_name": listener("name")}
+		return m
+	})
+	return append(certificateRefs, options...)
+}
+
+// listenerStatus returns the status_listener_condition metric with a series
+// per condition of each listener in the status of a Gateway or ListenerSet,
+// set to 1 if the condition status is True, and the
+// status_listener_supported_kinds metric with the route kinds each listener
+// supports.
+func listenerStatus(kind string) []customresourcestate.Generator {
+	conditions := crs.ForEachIndex(crs.Path{"status", "listeners"}, maxListeners, func(listener func(...string) crs.Path) customresourcestate.Generator {
+		m := crs.Gauge(
+			"status_listener_condition",
+			"Status conditions of the "+kind+" listeners",
+			listener("conditions"),
+			crs.Labels{"type": {"type"}, "reason": {"reason"}},
+			crs.Path{"status"},
+		)
+		m.Labels.LabelsFromPath = crs.Labels{"listener_name": listener("name")}
+		return m
+	})
+	supportedKinds := crs.ForEachIndex(crs.Path{"status", "listeners"}, maxListeners, func(listener func(...string) crs.Path) customresourcestate.Generator {
+		m := crs.Info(
+			"status_listener_supported_kinds",
+			"Route kinds supported by the "+kind+" listeners",
+			listener("supportedKinds"),
+			crs.Labels{"group": {"group"}, "kind": {"kind"}},
+		)
+		m.Labels.LabelsFromPath = crs.Labels{"listener_name": listener("name")}
+		return m
+	})
+	return append(conditions, supportedKinds...)
+}
+
+func gatewayClass(gvk customresourcestate.GroupVersionKind, _ options) customresourcestate.Resource {
+	return crs.Resource(prefix(gvk.Kind), gvk, false,
+		crs.Metrics(
+			crs.Info("info", "GatewayClass information", nil, crs.Labels{
+				"controller_name": {"spec", "controllerName"},
+			}),
+		),
+		crs.MetadataMetrics(),
+		crs.Metrics(
+			crs.StatusConditions(),
+			crs.StatusLastTransitionTime(),
+			crs.Info("status_supported_features", "List of supported features for the GatewayClass", crs.Path{"status", "supportedFeatures"}, crs.Labels{
+				"features": {},
+			}),
+		),
+		crs.StatusObservedGeneration("Accepted"),
+	)
+}
+
+// route returns a func that returns the config of a route kind. Routes
+// without hostnames, like TCPRoute and UDPRoute, have no hostname_info metric.
+// rules returns the metrics of the rules specific to the kind, if any.
+func route(hostnames bool, rules func(opts options) []customresourcestate.Generator) func(gvk customresourcestate.GroupVersionKind, opts options) customresourcestate.Resource {
+	return func(gvk customresourcestate.GroupVersionKind, opts options) customresourcestate.Resource {
+		metrics := crs.MetadataMetrics()
+		if hostnames {
+			metrics = append(metrics, crs.Hostnames())
+		}
+		var ruleMetrics []customresourcestate.Generator
+		if rules != nil {
+			ruleMetrics = rules(opts)
+		}
+		return crs.Resource(prefix(gvk.Kind), gvk, true,
+			metrics,
+			crs.ParentRefs(gvk.Kind),
+			crs.Rules(gvk.Kind),
+			crs.BackendRefs(gvk.Kind),
+			ruleMetrics,
+			crs.ParentConditions(gvk.Kind),
+			crs.ParentObservedGeneration(gvk.Kind),
+		)
+	}
+}
+
+func backendTLSPolicy(gvk customresourcestate.GroupVersionKind, _ options) customresourcestate.Resource {
+	return crs.Resource(prefix(gvk.Kind), gvk, true,
+		crs.MetadataMetrics(),
+		crs.PolicyTargetRefs(gvk.Kind),
+		crs.AncestorConditions(gvk.Kind),
+		crs.AncestorObservedGeneration(gvk.Kind),
+	)
+}
+
+func referenceGrant(gvk customresourcestate.GroupVersionKind, _ options) customresourcestate.Resource {
+	return crs.Resource(prefix(gvk.Kind), gvk, true,
+		crs.MetadataMetrics(),
+		crs.Metrics(
+			crs.Info("from_info", "Objects in other namespaces that may refer to the objects in to_info", crs.Path{"spec", "from"}, crs.Labels{
+				"from_group":     {"group"},
+				"from_kind":      {"kind"},
+				"from_namespace": {"namespace"},
+			}),
+			crs.Info("to_info", "Objects in the namespace of the ReferenceGrant that may be referred to", crs.Path{"spec", "to"}, crs.Labels{
+				"to_group": {"group"},
+				"to_kind":  {"kind"},
+				"to_name":  {"name"},
+			}),
+		),
+	)
+}
+
+// backendTrafficPolicy returns the config of BackendLBPolicy, or of
+// XBackendTrafficPolicy, which it was renamed to in Gateway API v1.3.0.
+func backendTrafficPolicy(gvk customresourcestate.GroupVersionKind, _ options) customresourcestate.Resource {
+	return crs.Resource(prefix(gvk.Kind), gvk, true,
+		crs.MetadataMetrics(),
+		crs.Metrics(
+			crs.TargetRefs(gvk.Kind),
+			crs.Info("session_persistence_info", "Session persistence of the backends the "+strings.ToLower(gvk.Kind)+" targets", crs.Path{"spec", "sessionPersistence"}, crs.Labels{
+				"type":                 {"type"},
+				"session_name":         {"sessionName"},
+				"absolute_timeout":     {"absoluteTimeout"},
+				"idle_timeout":         {"idleTimeout"},
+				"cookie_lifetime_type": {"cookieConfig", "lifetimeType"},
+			}),
+		),
+		crs.AncestorConditions(gvk.Kind),
+		crs.AncestorObservedGeneration(gvk.Kind),
+	)
+}
+
+// listenerSet returns the config of XListenerSet, whose listeners are merged
+// into the listeners of its parent Gateway.
+func listenerSet(gvk customresourcestate.GroupVersionKind, _ options) customresourcestate.Resource {
+	return crs.Resource(prefix(gvk.Kind), gvk, true,
+		crs.MetadataMetrics(),
+		crs.Metrics(
+			crs.Info("parent_info", "Gateway that the ListenerSet is attached to", crs.Path{"spec", "parentRef"}, crs.Labels{
+				"parent_group":     {"group"},
+				"parent_kind":      {"kind"},
+				"parent_name":      {"name"},
+				"parent_namespace": {"namespace"},
+			}),
+			crs.Info("listener_info", "ListenerSet listener information", crs.Path{"spec", "listeners"}, crs.Labels{
+				"listener_name":                  {"name"},
+				"port":                           {"port"},
+				"protocol":                       {"protocol"},
+				"hostname":                       {"hostname"},
+				"tls_mode":                       {"tls", "mode"},
+				"allowed_routes_namespaces_from": {"allowedRoutes", "namespaces", "from"},
+			}),
+			crs.StatusConditions(),
+			crs.StatusLastTransitionTime(),
+			crs.Gauge("status_listener_attached_routes", "Number of attached routes for a listener", crs.Path{"status", "listeners"}, crs.Labels{
+				"listener_name": {"name"},
+			}, crs.Path{"attachedRoutes"}),
+		),
+		crs.StatusObservedGeneration("Accepted", "Programmed"),
+		listenerStatus("ListenerSet"),
+	)
+}
+
+// prefix returns the metric name prefix of a Gateway API kind.
+func prefix(kind string) string {
+	return "gatewayapi_" + strings.ToLower(kind)
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
+
+	"github.com/kuadrant/gateway-api-state-metrics/pkg/crs"
+)
+
+const kuadrantGroup = "kuadrant.io"
+
+// maxLimitRates is the number of rates of each limit of a RateLimitPolicy
+// with metrics. The CRD doesn't limit them, but each rate needs metrics of
+// its own in the config, see crs.ForEachIndex.
+const maxLimitRates = 4
+
+// maxLimitCounters is the number of counters, and maxLimitPredicates the
+// number of when predicates, of each limit of a RateLimitPolicy that are
+// labels of limit_info.
+const (
+	maxLimitCounters   = 4
+	maxLimitPredicates = 4
+)
+
+// maxEndpoints is the number of endpoints of a DNSRecord with metrics for
+// their targets. The CRD doesn't limit them, but each endpoint needs metrics
+// of its own in the config, see crs.ForEachIndex.
+const maxEndpoints = 16
+
+// kuadrantKinds are the Kuadrant kinds, in the order they are written to the
+// config.
+var kuadrantKinds = []struct {
+	kind     string
+	version  string
+	resource func(gvk customresourcestate.GroupVersionKind) customresourcestate.Resource
+}{
+	{"TLSPolicy", "v1", tlsPolicy},
+	{"DNSPolicy", "v1", dnsPolicy},
+	{"RateLimitPolicy", "v1", rateLimitPolicy},
+	{"AuthPolicy", "v1", authPolicy},
+	{"DNSRecord", "v1alpha1", dnsRecord},
+}
+
+// kuadrantResources returns the configs of the Kuadrant kinds, in the order
+// they are written to the config.
+func kuadrantResources() []customresourcestate.Resource {
+	var resources []customresourcestate.Resource
+	for _, k := range kuadrantKinds {
+		gvk := customresourcestate.GroupVersionKind{Group: kuadrantGroup, Version: k.version, Kind: k.kind}
+		resources = append(resources, k.resource(gvk))
+	}
+	return resources
+}
+
+// kuadrantStatus returns the status condition metrics of a Kuadrant kind,
+// which records the generation its status was set for in
+// status.observedGeneration rather than in each condition.
+func kuadrantStatus() []customresourcestate.Generator {
+	return crs.Metrics(
+		crs.StatusConditions(),
+		crs.StatusLastTransitionTime(),
+		crs.Optional(crs.Gauge("status_observed_generation", "Generation of the object the status was set for", crs.Path{"status", "observedGeneration"}, nil, nil)),
+	)
+}
+
+// policySections are the sections of the spec of a Kuadrant policy with the
+// same fields: the spec itself, and the defaults and overrides the policy sets
+// for the policies of the objects below its target.
+var policySections = []struct {
+	name string
+	path crs.Path
+}{
+	{"", crs.Path{"spec"}},
+	{"defaults", crs.Path{"spec", "defaults"}},
+	{"overrides", crs.Path{"spec", "overrides"}},
+}
+
+// forEachSection returns the metrics returned by metrics for each section of
+// a policy spec. metrics is given the path of a field of the section. The
+// metrics of defaults and overrides have a section label with the name of
+// their section. Each family is next to each other in the config, so metrics
+// that return several families must be called once per family.
+func forEachSection(metrics func(section func(fields ...string) crs.Path) []customresourcestate.Generator) []customresourcestate.Generator {
+	var result []customresourcestate.Generator
+	for _, s := range policySections {
+		section := func(fields ...string) crs.Path {
+			return append(append(crs.Path{}, s.path...), fields...)
+		}
+		for _, m := range metrics(section) {
+			if s.name != "" {
+				if m.Labels.CommonLabels == nil {
+					m.Labels.CommonLabels = map[string]string{}
+				}
+				m.Labels.CommonLabels["section"] = s.name
+			}
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// namedEntries returns the name metric with a series per entry of the map at
+// field of each section of a policy spec, like the limits of a
+// RateLimitPolicy. label is the label of the key of the entry.
+func namedEntries(name, help string, label string, labels crs.Labels, field ...string) []customresourcestate.Generator {
+	return forEachSection(func(section func(...string) crs.Path) []customresourcestate.Generator {
+		m := crs.Info(name, help, section(field...), labels)
+		m.Each.Info.LabelFromKey = label
+		return crs.Metrics(m)
+	})
+}
+
+func tlsPolicy(gvk customresourcestate.GroupVersionKind) customresourcestate.Resource {
+	return crs.Resource(prefix(gvk.Kind), gvk, true,
+		crs.MetadataMetrics(),
+		crs.Metrics(crs.TargetRef(gvk.Kind)),
+		kuadrantStatus(),
+	)
+}
+
+func dnsPolicy(gvk customresourcestate.GroupVersionKind) customresourcestate.Resource {
+	return crs.Resource(prefix(gvk.Kind), gvk, true,
+		crs.MetadataMetrics(),
+		crs.Metrics(
+			crs.TargetRef(gvk.Kind),
+			crs.Info("health_check_info", "Health checks of the endpoints the dnspolicy publishes", crs.Path{"spec", "healthCheck"}, crs.Labels{
+				"path":              {"path"},
+				"port":              {"port"},
+				"protocol":          {"protocol"},
+				"interval":          {"interval"},
+				"failure_threshold": {"failureThreshold"},
+			}),
+			crs.Info("load_balancing_info", "Load balancing of the endpoints the dnspolicy publishes", crs.Path{"spec", "loadBalancing"}, crs.Labels{
+				"weight":      {"weight"},
+				"geo":         {"geo"},
+				"default_geo": {"defaultGeo"},
+			}),
+		),
+		kuadrantStatus(),
+	)
+}
+
+// rateLimitPolicy returns the config of RateLimitPolicy, with the limits of
+// each section of its spec.
+func rateLimitPolicy(gvk customresourcestate.GroupVersionKind) customresourcestate.Resource {
+	return crs.Resource(prefix(gvk.Kind), gvk, true,
+		crs.MetadataMetrics(),
+		crs.Metrics(crs.TargetRef(gvk.Kind)),
+		namedEntries("limit_info", fmt.Sprintf("Limits of the ratelimitpolicy, with the first %d counters and %d when predicates of each limit", maxLimitCounters, maxLimitPredicates), "limit_name", limitLabels(), "limits"),
+		limitRates(),
+		kuadrantStatus(),
+	)
+}
+
+// limitLabels returns the labels of limit_info with the counters and when
+// predicates of a limit, like counter_0 for the expression of its first
+// counter. kube-state-metrics adds a series for every limit to a metric of
+// the limits unless its value is a number, and counters and predicates only
+// have strings, so they can't have series of their own like the rates.
+func limitLabels() crs.Labels {
+	labels := crs.Labels{}
+	for i := 0; i < maxLimitCounters; i++ {
+		labels["counter_"+strconv.Itoa(i)] = []string{"counters", strconv.Itoa(i), "expression"}
+	}
+	for i := 0; i < maxLimitPredicates; i++ {
+		labels["predicate_"+strconv.Itoa(i)] = []string{"when", strconv.Itoa(i), "predicate"}
+	}
+	return labels
+}
+
+// limitRates returns the limit_rate metric with a series per rate of each
+// limit of a RateLimitPolicy, set to the number of requests the rate allows
+// in its window. Limits with fewer rates have no series for the indexes they
+// don't have, as the limit of a rate is a number.
+func limitRates() []customresourcestate.Generator {
+	help := fmt.Sprintf("Number of requests the first %d rates of each limit of the ratelimitpolicy allow in their window", maxLimitRates)
+	return forEachSection(func(section func(...string) crs.Path) []customresourcestate.Generator {
+		return crs.WithIndexLabel("rate_index", crs.ForEachIndex(crs.Path{"rates"}, maxLimitRates, func(rate func(...string) crs.Path) customresourcestate.Generator {
+			m := crs.Gauge("limit_rate", help, section("limits"), crs.Labels{"window": rate("window")}, rate("limit"))
+			m.Each.Gauge.LabelFromKey = "limit_name"
+			return m
+		}))
+	})
+}
+
+// authenticationMethods and authorizationMethods are the labels of the
+// methods of the authentication and authorization rules of an AuthPolicy, and
+// the path of a field each method requires or defaults, or of the method
+// itself for methods without such a field. kube-state-metrics also resolves
+// these paths against the map of the rules, where the paths of a single key
+// match a rule named after the method.
+var (
+	authenticationMethods = crs.Labels{
+		"anonymous":               {"anonymous"},
+		"api_key":                 {"apiKey", "allNamespaces"},
+		"jwt":                     {"jwt", "issuerUrl"},
+		"kubernetes_token_review": {"kubernetesTokenReview"},
+		"oauth2_introspection":    {"oauth2Introspection", "endpoint"},
+		"plain":                   {"plain"},
+		"x509":                    {"x509", "allNamespaces"},
+	}
+	authorizationMethods = crs.Labels{
+		"kubernetes_subject_access_review": {"kubernetesSubjectAccessReview"},
+		"opa":                              {"opa", "allValues"},
+		"pattern_matching":                 {"patternMatching", "patterns"},
+		"spicedb":                          {"spicedb", "endpoint"},
+	}
+)
+
+// authPolicy returns the config of AuthPolicy, with the rules of each section
+// of its spec. The method of each authentication and authorization rule is
+// the key of an object, which kube-state-metrics can't expose as a label
+// value, so rules have a label per method, only set for the method they use.
+func authPolicy(gvk customresourcestate.GroupVersionKind) customresourcestate.Resource {
+	return crs.Resource(prefix(gvk.Kind), gvk, true,
+		crs.MetadataMetrics(),
+		crs.Metrics(crs.TargetRef(gvk.Kind)),
+		namedEntries("authentication_info", "Authentication rules of the authpolicy, with a label for the method of each rule", "authentication_name", authenticationMethods, "rules", "authentication"),
+		namedEntries("authorization_info", "Authorization rules of the authpolicy, with a label for the method of each rule", "authorization_name", authorizationMethods, "rules", "authorization"),
+		namedEntries("metadata_info", "Metadata rules of the authpolicy", "metadata_name", nil, "rules", "metadata"),
+		namedEntries("callback_info", "Callback rules of the authpolicy", "callback_name", nil, "rules", "callbacks"),
+		namedEntries("response_info", "Responses the authpolicy configures", "response", nil, "rules", "response"),
+		kuadrantStatus(),
+	)
+}
+
+// dnsRecord returns the config of DNSRecord, the records Kuadrant publishes
+// for the listeners a DNSPolicy targets. Its metrics are prefixed with
+// kuadrant_ and have the root domain of the record as a label.
+func dnsRecord(gvk customresourcestate.GroupVersionKind) customresourcestate.Resource {
+	r := crs.Resource("kuadrant_"+strings.ToLower(gvk.Kind), gvk, true,
+		crs.Metrics(
+			crs.Gauge("created", "created timestamp", crs.Path{"metadata", "creationTimestamp"}, nil, nil),
+			crs.Gauge("metadata_generation", "Generation of the desired state of the object", crs.Path{"metadata", "generation"}, nil, nil),
+			crs.Info("endpoint_info", "Endpoints the dnsrecord publishes", crs.Path{"spec", "endpoints"}, crs.Labels{
+				"dns_name":       {"dnsName"},
+				"record_type":    {"recordType"},
+				"set_identifier": {"setIdentifier"},
+				"record_ttl":     {"recordTTL"},
+			}),
+		),
+		endpointTargets(),
+		crs.Metrics(
+			crs.Info("status_root_domain_owners", "root domain owners (the ids of controllers managing this root domain)", crs.Path{"status", "domainOwners"}, crs.Labels{
+				"owner": {},
+			}),
+		),
+		kuadrantStatus(),
+		crs.Metrics(
+			crs.Optional(crs.Gauge("health_check_status", "status condition of the health checks of the dnsrecord", crs.Path{"status", "healthCheck", "conditions"}, crs.Labels{
+				"type":   {"type"},
+				"reason": {"reason"},
+			}, crs.Path{"status"})),
+			crs.Info("health_check_probe_info", "Health check probes of each endpoint address of the dnsrecord", crs.Path{"status", "healthCheck", "probes"}, crs.Labels{
+				"probe_id":   {"id"},
+				"host":       {"host"},
+				"ip_address": {"ipAddress"},
+				"synced":     {"synced"},
+				"healthy":    {"conditions", "[type=Healthy]", "status"},
+				"reason":     {"conditions", "[type=Healthy]", "reason"},
+			}),
+		),
+	)
+	r.Labels.LabelsFromPath["rootDomain"] = []string{"spec", "rootHost"}
+	return r
+}
+
+// endpointTargets returns the endpoint_target_info metric with a series per
+// target of each of the first maxEndpoints endpoints of a DNSRecord, with the
+// labels of the endpoint. kube-state-metrics can't iterate the targets of each
+// endpoint while reading labels of the endpoint, see crs.ForEachIndex.
+func endpointTargets() []customresourcestate.Generator {
+	help := fmt.Sprintf("Targets of the first %d endpoints the dnsrecord publishes", maxEndpoints)
+	return crs.WithIndexLabel("endpoint_index", crs.ForEachIndex(crs.Path{"spec", "endpoints"}, maxEndpoints, func(endpoint func(...string) crs.Path) customresourcestate.Generator {
+		m := crs.Info("endpoint_target_info", help, endpoint("targets"), crs.Labels{"target": {}})
+		m.Labels.LabelsFromPath = crs.Labels{
+			"dns_name":       endpoint("dnsName"),
+			"record_type":    endpoint("recordType"),
+			"set_identifier": endpoint("setIdentifier"),
+		}
+		return m
+	}))
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
+)
+
+func TestRateLimitPolicyLimits(t *testing.T) {
+	resource := rateLimitPolicy(customresourcestate.GroupVersionKind{Group: kuadrantGroup, Version: "v1", Kind: "RateLimitPolicy"})
+	var sections []string
+	for _, m := range resource.Metrics {
+		if m.Name != "limit_info" {
+			continue
+		}
+		sections = append(sections, m.Labels.CommonLabels["section"])
+		var labels []string
+		for label := range m.Each.Info.LabelsFromPath {
+			labels = append(labels, label)
+		}
+		if len(labels) != maxLimitCounters+maxLimitPredicates {
+			t.Errorf("expected %d counter and predicate labels, got %v", maxLimitCounters+maxLimitPredicates, labels)
+		}
+		for _, label := range []string{"counter_0", "counter_3", "predicate_0", "predicate_3"} {
+			if m.Each.Info.LabelsFromPath[label] == nil {
+				t.Errorf("expected limit_info to have a %s label, got %v", label, labels)
+			}
+		}
+	}
+	if got := strings.Join(sections, ","); got != ",defaults,overrides" {
+		t.Errorf("expected limit_info for the spec, defaults and overrides, got sections %q", got)
+	}
+}
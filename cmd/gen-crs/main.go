@@ -0,0 +1,81 @@
+// Command gen-crs generates the default CustomResourceState config for the
+// Gateway API kinds, config/default/custom-resource-state.yaml, and the config
+// for the Kuadrant kinds.
+//
+//	go run ./cmd/gen-crs -o config/default/custom-resource-state.yaml
+//
+// Each kind is configured with its newest version in the Gateway API release
+// set with -gateway-api-version, e.g. v1 for Gateway in v1.0.0 and later:
+//
+//	go run ./cmd/gen-crs -gateway-api-version v1.1.0
+//
+// With -experimental, the config also has the kinds and fields of the
+// experimental channel of the release, as in
+// config/experimental/custom-resource-state.yaml:
+//
+//	go run ./cmd/gen-crs -experimental -gateway-api-version v1.3.0
+//
+// The metrics of route matches and filters only have the types of the
+// matches and filters, not their values such as paths and header values,
+// unless -route-values is set. There is a series per match and filter either
+// way, the values only add labels to them:
+//
+//	go run ./cmd/gen-crs -route-values
+//
+// With -kuadrant, the config only has the Kuadrant kinds, as in
+// config/kuadrant/custom-resource-state-kuadrant.yaml, which cmd/merge-crs
+// merges into the default config:
+//
+//	go run ./cmd/gen-crs -kuadrant -o config/kuadrant/custom-resource-state-kuadrant.yaml
+package main
+
+import (
+	"bytes"
+	"flag"
+	"log"
+	"os"
+
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
+
+	"github.com/kuadrant/gateway-api-state-metrics/pkg/crs"
+)
+
+const header = "Code generated by cmd/gen-crs. DO NOT EDIT."
+
+func main() {
+	output := flag.String("o", "", "file to write the config to, defaults to stdout")
+	gatewayAPIVersion := flag.String("gateway-api-version", defaultGatewayAPIVersion, "Gateway API release to select the versions of the kinds for")
+	experimental := flag.Bool("experimental", false, "include the kinds and fields of the experimental channel")
+	routeValues := flag.Bool("route-values", false, "include the values of route matches and filters, such as paths and header values, as labels")
+	kuadrant := flag.Bool("kuadrant", false, "generate the config of the Kuadrant kinds instead of the Gateway API kinds")
+	flag.Parse()
+
+	var resources []customresourcestate.Resource
+	if *kuadrant {
+		resources = kuadrantResources()
+	} else {
+		release, err := newGatewayAPIRelease(*gatewayAPIVersion)
+		if err != nil {
+			log.Fatal(err)
+		}
+		resources, err = gatewayAPIResources(release, options{experimental: *experimental, routeValues: *routeValues})
+		if err != nil {
+			log.Fatalf("failed to generate config: %v", err)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := crs.Write(buf, header, resources...); err != nil {
+		log.Fatalf("failed to generate config: %v", err)
+	}
+
+	if *output == "" {
+		if _, err := os.Stdout.Write(buf.Bytes()); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if err := os.WriteFile(*output, buf.Bytes(), 0o644); err != nil {
+		log.Fatalf("failed to write %s: %v", *output, err)
+	}
+}
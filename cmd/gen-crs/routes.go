@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
+
+	"github.com/kuadrant/gateway-api-state-metrics/pkg/crs"
+)
+
+// routeFilter has the labels of the fields of a filter type of route rules.
+// The labels in values are left out of the config without route values.
+type routeFilter struct {
+	labels map[string]crs.Path
+	values map[string]crs.Path
+}
+
+// requestMirrorFilter is the RequestMirror filter of HTTPRoute and GRPCRoute
+// rules.
+var requestMirrorFilter = routeFilter{
+	labels: map[string]crs.Path{
+		"mirror_backend_kind": {"requestMirror", "backendRef", "kind"},
+	},
+	values: map[string]crs.Path{
+		"mirror_backend_name":      {"requestMirror", "backendRef", "name"},
+		"mirror_backend_namespace": {"requestMirror", "backendRef", "namespace"},
+		"mirror_backend_port":      {"requestMirror", "backendRef", "port"},
+	},
+}
+
+// extensionRefFilter is the ExtensionRef filter of HTTPRoute and GRPCRoute
+// rules.
+var extensionRefFilter = routeFilter{
+	labels: map[string]crs.Path{
+		"extension_group": {"extensionRef", "group"},
+		"extension_kind":  {"extensionRef", "kind"},
+	},
+	values: map[string]crs.Path{
+		"extension_name": {"extensionRef", "name"},
+	},
+}
+
+// httpRouteFilters are the filter types of HTTPRoute rules with fields that
+// are labels. The header modifier filters have none.
+var httpRouteFilters = []routeFilter{
+	// RequestRedirect
+	{
+		labels: map[string]crs.Path{
+			"redirect_scheme":      {"requestRedirect", "scheme"},
+			"redirect_status_code": {"requestRedirect", "statusCode"},
+			"redirect_path_type":   {"requestRedirect", "path", "type"},
+		},
+		values: map[string]crs.Path{
+			"redirect_hostname":          {"requestRedirect", "hostname"},
+			"redirect_port":              {"requestRedirect", "port"},
+			"redirect_replace_full_path": {"requestRedirect", "path", "replaceFullPath"},
+			"redirect_replace_prefix":    {"requestRedirect", "path", "replacePrefixMatch"},
+		},
+	},
+	// URLRewrite
+	{
+		labels: map[string]crs.Path{
+			"rewrite_path_type": {"urlRewrite", "path", "type"},
+		},
+		values: map[string]crs.Path{
+			"rewrite_hostname":          {"urlRewrite", "hostname"},
+			"rewrite_replace_full_path": {"urlRewrite", "path", "replaceFullPath"},
+			"rewrite_replace_prefix":    {"urlRewrite", "path", "replacePrefixMatch"},
+		},
+	},
+	requestMirrorFilter,
+	extensionRefFilter,
+}
+
+// httpRouteRules returns the metrics of the matches, filters and timeouts of
+// the rules of an HTTPRoute, which name the matches and filters they leave out
+// in their help text. Without opts.routeValues the labels with the
+// values of matches and filters, like paths, hostnames and header values, are
+// left out: there is still a series per match and filter, but only with their
+// types.
+func httpRouteRules(opts options) []customresourcestate.Generator {
+	matchLabels := crs.Labels{
+		"path_type": {"path", "type"},
+		"method":    {"method"},
+	}
+	headerLabels := crs.Labels{
+		"match_type":  {"type"},
+		"header_name": {"name"},
+	}
+	queryParamLabels := crs.Labels{
+		"match_type":       {"type"},
+		"query_param_name": {"name"},
+	}
+	if opts.routeValues {
+		matchLabels["path_value"] = []string{"path", "value"}
+		headerLabels["header_value"] = []string{"value"}
+		queryParamLabels["query_param_value"] = []string{"value"}
+	}
+
+	var metrics []customresourcestate.Generator
+	metrics = append(metrics, crs.ForEachRuleEntry("matches", crs.MaxMatches, "match_index", func(match func(...string) crs.Path) customresourcestate.Generator {
+		return crs.Info("match_info", fmt.Sprintf("Path and method matches of the httproute rules, only the first %d matches of the first %d rules", crs.MaxMatches, crs.MaxDetailedRules), match(), matchLabels)
+	})...)
+	metrics = append(metrics, crs.ForEachRuleEntry("matches", crs.MaxMatches, "match_index", func(match func(...string) crs.Path) customresourcestate.Generator {
+		return crs.Info("match_header_info", fmt.Sprintf("Header matches of the httproute rules, only of the first %d matches of the first %d rules", crs.MaxMatches, crs.MaxDetailedRules), match("headers"), headerLabels)
+	})...)
+	metrics = append(metrics, crs.ForEachRuleEntry("matches", crs.MaxMatches, "match_index", func(match func(...string) crs.Path) customresourcestate.Generator {
+		return crs.Info("match_query_param_info", fmt.Sprintf("Query parameter matches of the httproute rules, only of the first %d matches of the first %d rules", crs.MaxMatches, crs.MaxDetailedRules), match("queryParams"), queryParamLabels)
+	})...)
+	metrics = append(metrics, ruleFilters("httproute", httpRouteFilters, opts)...)
+	metrics = append(metrics, crs.ForEachRule(crs.MaxDetailedRules, func(rule func(...string) crs.Path) customresourcestate.Generator {
+		return crs.Info("timeouts_info", fmt.Sprintf("Timeouts of the httproute rules, only of the first %d rules", crs.MaxDetailedRules), rule("timeouts"), crs.Labels{
+			"request":         {"request"},
+			"backend_request": {"backendRequest"},
+		})
+	})...)
+	return metrics
+}
+
+// grpcRouteFilters are the filter types of GRPCRoute rules with fields that
+// are labels.
+var grpcRouteFilters = []routeFilter{
+	requestMirrorFilter,
+	extensionRefFilter,
+}
+
+// grpcRouteRules returns the metrics of the method and header matches and the
+// filters of the rules of a GRPCRoute. The service and method of the matches
+// are always included, only the values of header matches and filters are left
+// out without opts.routeValues.
+func grpcRouteRules(opts options) []customresourcestate.Generator {
+	headerLabels := crs.Labels{
+		"match_type":  {"type"},
+		"header_name": {"name"},
+	}
+	if opts.routeValues {
+		headerLabels["header_value"] = []string{"value"}
+	}
+
+	var metrics []customresourcestate.Generator
+	metrics = append(metrics, crs.ForEachRuleEntry("matches", crs.MaxMatches, "match_index", func(match func(...string) crs.Path) customresourcestate.Generator {
+		return crs.Info("match_info", fmt.Sprintf("Method matches of the grpcroute rules, only the first %d matches of the first %d rules", crs.MaxMatches, crs.MaxDetailedRules), match(), crs.Labels{
+			"match_type": {"method", "type"},
+			"service":    {"method", "service"},
+			"method":     {"method", "method"},
+		})
+	})...)
+	metrics = append(metrics, crs.ForEachRuleEntry("matches", crs.MaxMatches, "match_index", func(match func(...string) crs.Path) customresourcestate.Generator {
+		return crs.Info("match_header_info", fmt.Sprintf("Header matches of the grpcroute rules, only of the first %d matches of the first %d rules", crs.MaxMatches, crs.MaxDetailedRules), match("headers"), headerLabels)
+	})...)
+	metrics = append(metrics, ruleFilters("grpcroute", grpcRouteFilters, opts)...)
+	return metrics
+}
+
+// ruleFilters returns the filter_info metric of a route kind, with a series
+// per filter of each rule. The filter_index label is the index of the filter
+// in the rule, so filters of the same type, like several RequestMirror
+// filters, have separate series. Each series has the labels of the fields of
+// its filter type.
+func ruleFilters(kind string, filters []routeFilter, opts options) []customresourcestate.Generator {
+	labels := crs.Labels{"type": {"type"}}
+	for _, f := range filters {
+		for label, path := range f.labels {
+			labels[label] = path
+		}
+		if opts.routeValues {
+			for label, path := range f.values {
+				labels[label] = path
+			}
+		}
+	}
+	return crs.ForEachRuleEntry("filters", crs.MaxFilters, "filter_index", func(filter func(...string) crs.Path) customresourcestate.Generator {
+		return crs.Info("filter_info", fmt.Sprintf("Filters of the %s rules, only the first %d filters of the first %d rules", kind, crs.MaxFilters, crs.MaxDetailedRules), filter(), labels)
+	})
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
+)
+
+// ruleLabels returns the labels of the metrics of route rules, by metric name.
+func ruleLabels(rules func(options) []customresourcestate.Generator, opts options) map[string][]string {
+	labels := map[string]map[string]bool{}
+	for _, m := range rules(opts) {
+		if labels[m.Name] == nil {
+			labels[m.Name] = map[string]bool{}
+		}
+		for label := range m.Each.Info.LabelsFromPath {
+			labels[m.Name][label] = true
+		}
+		for label := range m.Labels.CommonLabels {
+			labels[m.Name][label] = true
+		}
+	}
+	result := map[string][]string{}
+	for name, set := range labels {
+		for label := range set {
+			result[name] = append(result[name], label)
+		}
+		sort.Strings(result[name])
+	}
+	return result
+}
+
+func TestHTTPRouteRulesWithoutValues(t *testing.T) {
+	expected := map[string]string{
+		"match_info":             "match_index method path_type rule_index",
+		"match_header_info":      "header_name match_index match_type rule_index",
+		"match_query_param_info": "match_index match_type query_param_name rule_index",
+		"filter_info": "extension_group extension_kind filter_index mirror_backend_kind redirect_path_type redirect_scheme " +
+			"redirect_status_code rewrite_path_type rule_index type",
+		"timeouts_info": "backend_request request rule_index",
+	}
+	actual := ruleLabels(httpRouteRules, options{})
+	for name, labels := range expected {
+		if got := strings.Join(actual[name], " "); got != labels {
+			t.Errorf("expected the labels of %s to be %s, got %s", name, labels, got)
+		}
+	}
+	if len(actual) != len(expected) {
+		t.Errorf("expected %d metrics, got %v", len(expected), actual)
+	}
+}
+
+func TestHTTPRouteRulesWithValues(t *testing.T) {
+	actual := ruleLabels(httpRouteRules, options{routeValues: true})
+	for name, labels := range map[string][]string{
+		"match_info":             {"path_value"},
+		"match_header_info":      {"header_value"},
+		"match_query_param_info": {"query_param_value"},
+		"filter_info":            {"redirect_hostname", "rewrite_replace_full_path", "mirror_backend_name", "extension_name"},
+	} {
+		for _, label := range labels {
+			if i := sort.SearchStrings(actual[name], label); i == len(actual[name]) || actual[name][i] != label {
+				t.Errorf("expected %s to have the %s label, got %v", name, label, actual[name])
+			}
+		}
+	}
+}
+
+func TestGRPCRouteRules(t *testing.T) {
+	for _, tc := range []struct {
+		opts     options
+		expected map[string]string
+	}{
+		{
+			opts: options{},
+			expected: map[string]string{
+				"match_info":        "match_index match_type method rule_index service",
+				"match_header_info": "header_name match_index match_type rule_index",
+				"filter_info":       "extension_group extension_kind filter_index mirror_backend_kind rule_index type",
+			},
+		},
+		{
+			opts: options{routeValues: true},
+			expected: map[string]string{
+				"match_info":        "match_index match_type method rule_index service",
+				"match_header_info": "header_name header_value match_index match_type rule_index",
+				"filter_info": "extension_group extension_kind extension_name filter_index mirror_backend_kind mirror_backend_name " +
+					"mirror_backend_namespace mirror_backend_port rule_index type",
+			},
+		},
+	} {
+		actual := ruleLabels(grpcRouteRules, tc.opts)
+		for name, labels := range tc.expected {
+			if got := strings.Join(actual[name], " "); got != labels {
+				t.Errorf("expected the labels of %s with %+v to be %s, got %s", name, tc.opts, labels, got)
+			}
+		}
+		if len(actual) != len(tc.expected) {
+			t.Errorf("expected %d metrics with %+v, got %v", len(tc.expected), tc.opts, actual)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+// defaultGatewayAPIVersion is the Gateway API release the default config is
+// generated for, the release of the CRDs in config/gateway-api/crd/standard.
+const defaultGatewayAPIVersion = "v0.8.0"
+
+// kindVersion is a version of a Gateway API kind and the release it was added
+// in.
+type kindVersion struct {
+	version string
+	since   string
+}
+
+// gatewayAPIVersions lists the versions of each Gateway API kind, newest
+// first. Kinds are configured with the newest version of the target release,
+// which is the storage version of the kind in that release.
+var gatewayAPIVersions = map[string][]kindVersion{
+	"GatewayClass":     {{"v1", "v1.0.0"}, {"v1beta1", "v0.5.0"}},
+	"Gateway":          {{"v1", "v1.0.0"}, {"v1beta1", "v0.5.0"}},
+	"HTTPRoute":        {{"v1", "v1.0.0"}, {"v1beta1", "v0.5.0"}},
+	"GRPCRoute":        {{"v1", "v1.1.0"}, {"v1alpha2", "v0.6.0"}},
+	"TCPRoute":         {{"v1alpha2", "v0.5.0"}},
+	"TLSRoute":         {{"v1alpha2", "v0.5.0"}},
+	"UDPRoute":         {{"v1alpha2", "v0.5.0"}},
+	"BackendTLSPolicy": {{"v1alpha3", "v1.1.0"}, {"v1alpha2", "v0.8.0"}},
+
+	// kinds of the experimental channel, BackendLBPolicy was renamed to
+	// XBackendTrafficPolicy in v1.3.0 but clusters upgraded from an earlier
+	// release keep its CRD and objects
+	"ReferenceGrant":        {{"v1beta1", "v0.6.0"}, {"v1alpha2", "v0.5.0"}},
+	"BackendLBPolicy":       {{"v1alpha2", "v1.1.0"}},
+	"XBackendTrafficPolicy": {{"v1alpha1", "v1.3.0"}},
+	"XListenerSet":          {{"v1alpha1", "v1.3.0"}},
+}
+
+// gatewayAPIRelease selects the versions of the Gateway API kinds for a
+// Gateway API release.
+type gatewayAPIRelease struct {
+	name    string
+	release *version.Version
+}
+
+// newGatewayAPIRelease returns the release for a version such as v1.1.0.
+func newGatewayAPIRelease(v string) (gatewayAPIRelease, error) {
+	release, err := version.ParseGeneric(v)
+	if err != nil {
+		return gatewayAPIRelease{}, fmt.Errorf("invalid Gateway API version %q: %w", v, err)
+	}
+	return gatewayAPIRelease{name: v, release: release}, nil
+}
+
+// version returns the newest version of kind in the release, or false if the
+// kind is not in the release.
+func (r gatewayAPIRelease) version(kind string) (string, bool) {
+	for _, v := range gatewayAPIVersions[kind] {
+		if r.release.AtLeast(version.MustParseGeneric(v.since)) {
+			return v.version, true
+		}
+	}
+	return "", false
+}
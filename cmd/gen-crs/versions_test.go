@@ -0,0 +1,104 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGatewayAPIReleaseVersion(t *testing.T) {
+	for _, tc := range []struct {
+		release  string
+		kind     string
+		expected string
+	}{
+		{release: "v0.8.0", kind: "Gateway", expected: "v1beta1"},
+		{release: "v0.8.0", kind: "GRPCRoute", expected: "v1alpha2"},
+		{release: "v0.8.0", kind: "BackendTLSPolicy", expected: "v1alpha2"},
+		{release: "v1.0.0", kind: "HTTPRoute", expected: "v1"},
+		{release: "v1.0.0", kind: "GRPCRoute", expected: "v1alpha2"},
+		{release: "v1.1.0", kind: "GRPCRoute", expected: "v1"},
+		{release: "v1.1.0", kind: "BackendTLSPolicy", expected: "v1alpha3"},
+		{release: "v1.2.1", kind: "TCPRoute", expected: "v1alpha2"},
+		{release: "v1.2.1", kind: "BackendLBPolicy", expected: "v1alpha2"},
+		{release: "v1.3.0", kind: "XListenerSet", expected: "v1alpha1"},
+		{release: "v1.3.0", kind: "BackendLBPolicy", expected: "v1alpha2"},
+	} {
+		release, err := newGatewayAPIRelease(tc.release)
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", tc.release, err)
+		}
+		version, ok := release.version(tc.kind)
+		if !ok {
+			t.Fatalf("expected %s to be in %s", tc.kind, tc.release)
+		}
+		if version != tc.expected {
+			t.Errorf("expected %s in %s to be %s, got %s", tc.kind, tc.release, tc.expected, version)
+		}
+	}
+}
+
+func TestGatewayAPIReleaseMissingKinds(t *testing.T) {
+	for _, tc := range []struct {
+		release string
+		kind    string
+	}{
+		{release: "v0.7.0", kind: "BackendTLSPolicy"},
+		{release: "v1.2.1", kind: "XListenerSet"},
+	} {
+		release, err := newGatewayAPIRelease(tc.release)
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", tc.release, err)
+		}
+		if version, ok := release.version(tc.kind); ok {
+			t.Errorf("expected %s not to be in %s, got %s", tc.kind, tc.release, version)
+		}
+	}
+}
+
+func TestGatewayAPIResources(t *testing.T) {
+	kinds := func(release string, experimental bool) []string {
+		r, err := newGatewayAPIRelease(release)
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", release, err)
+		}
+		resources, err := gatewayAPIResources(r, options{experimental: experimental, routeValues: true})
+		if err != nil {
+			t.Fatalf("failed to generate resources for %s: %v", release, err)
+		}
+		var kinds []string
+		for _, resource := range resources {
+			kinds = append(kinds, resource.GroupVersionKind.Kind)
+		}
+		return kinds
+	}
+
+	standard := "Gateway GatewayClass HTTPRoute GRPCRoute TCPRoute TLSRoute UDPRoute BackendTLSPolicy"
+	for _, tc := range []struct {
+		release      string
+		experimental bool
+		expected     string
+	}{
+		{release: "v1.3.0", experimental: false, expected: standard},
+		{release: "v1.2.1", experimental: true, expected: standard + " ReferenceGrant BackendLBPolicy"},
+		{release: "v1.3.0", experimental: true, expected: standard + " ReferenceGrant BackendLBPolicy XBackendTrafficPolicy XListenerSet"},
+	} {
+		if actual := strings.Join(kinds(tc.release, tc.experimental), " "); actual != tc.expected {
+			t.Errorf("expected the kinds of %s (experimental %t) to be %s, got %s", tc.release, tc.experimental, tc.expected, actual)
+		}
+	}
+
+	r, err := newGatewayAPIRelease("v0.7.0")
+	if err != nil {
+		t.Fatalf("failed to parse v0.7.0: %v", err)
+	}
+	expected := "BackendTLSPolicy is not in Gateway API v0.7.0"
+	if _, err := gatewayAPIResources(r, options{routeValues: true}); err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
+
+func TestNewGatewayAPIReleaseInvalid(t *testing.T) {
+	if _, err := newGatewayAPIRelease("latest"); err == nil {
+		t.Error("expected an invalid version to fail")
+	}
+}
@@ -0,0 +1,83 @@
+// Command gen-metrics-docs generates the metrics reference, METRICS.md, from
+// CustomResourceState configs, with a section per config:
+//
+//	go run ./cmd/gen-metrics-docs -o METRICS.md \
+//	  config/default/custom-resource-state.yaml \
+//	  config/kuadrant/custom-resource-state-kuadrant.yaml
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
+
+	"github.com/kuadrant/gateway-api-state-metrics/pkg/crs"
+)
+
+const intro = `<!-- Code generated by cmd/gen-metrics-docs. DO NOT EDIT. -->
+
+# Metrics
+
+The metrics generated by kube-state-metrics for each kind in the CustomResourceState configs.
+To change this file, change the configs and run ` + "`make generate-metrics-docs`" + `.
+
+Besides the labels listed here, kube-state-metrics adds the ` + "`customresource_group`" + `,
+` + "`customresource_kind` and `customresource_version`" + ` labels to all metrics.
+
+A metric has a series for each object at its path, or a single series if the path is ` + "`.`" + `.
+The value and label paths of a metric are relative to its path, and a ` + "`*`" + ` label adds a label for each
+key of the map at its path.
+`
+
+func main() {
+	output := flag.String("o", "", "file to write the reference to, defaults to stdout")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-o output] config...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString(intro)
+	for _, path := range flag.Args() {
+		resources, err := read(path)
+		if err != nil {
+			log.Fatalf("failed to read %s: %v", path, err)
+		}
+		name := filepath.ToSlash(filepath.Clean(path))
+		fmt.Fprintf(buf, "\n## [%s](./%s)\n\n", name, name)
+		if err := crs.WriteMarkdown(buf, resources...); err != nil {
+			log.Fatalf("failed to write the metrics of %s: %v", path, err)
+		}
+	}
+	out := bytes.TrimRight(buf.Bytes(), "\n")
+	out = append(out, '\n')
+
+	if *output == "" {
+		if _, err := os.Stdout.Write(out); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if err := os.WriteFile(*output, out, 0o644); err != nil {
+		log.Fatalf("failed to write %s: %v", *output, err)
+	}
+}
+
+func read(path string) ([]customresourcestate.Resource, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return crs.Read(f)
+}
@@ -0,0 +1,75 @@
+// Command merge-crs merges the resources of CustomResourceState configs into
+// a single config, e.g. to add the Kuadrant kinds to the default config:
+//
+//	go run ./cmd/merge-crs -o config/kuadrant/custom-resource-state.yaml \
+//	  config/default/custom-resource-state.yaml \
+//	  config/kuadrant/custom-resource-state-kuadrant.yaml
+//
+// Each kind can only be configured once and resources can't share a
+// metricNamePrefix.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
+
+	"github.com/kuadrant/gateway-api-state-metrics/pkg/crs"
+)
+
+func main() {
+	output := flag.String("o", "", "file to write the merged config to, defaults to stdout")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-o output] config...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var configs [][]customresourcestate.Resource
+	for _, path := range flag.Args() {
+		resources, err := read(path)
+		if err != nil {
+			log.Fatalf("failed to read %s: %v", path, err)
+		}
+		configs = append(configs, resources)
+	}
+
+	merged, err := crs.Merge(configs...)
+	if err != nil {
+		log.Fatalf("failed to merge configs: %v", err)
+	}
+
+	header := fmt.Sprintf("Code generated by cmd/merge-crs from %s. DO NOT EDIT.", strings.Join(flag.Args(), ", "))
+	buf := &bytes.Buffer{}
+	if err := crs.Write(buf, header, merged...); err != nil {
+		log.Fatalf("failed to write merged config: %v", err)
+	}
+
+	if *output == "" {
+		if _, err := os.Stdout.Write(buf.Bytes()); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if err := os.WriteFile(*output, buf.Bytes(), 0o644); err != nil {
+		log.Fatalf("failed to write %s: %v", *output, err)
+	}
+}
+
+func read(path string) ([]customresourcestate.Resource, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return crs.Read(f)
+}
@@ -0,0 +1,432 @@
+package crs
+
+import (
+	"strconv"
+	"strings"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
+)
+
+// MaxParents is the number of parents in the status of a route with metrics
+// for their conditions. The CRDs allow 32, but each parent needs metrics of
+// its own in the config, see ForEachIndex.
+const MaxParents = 4
+
+// MaxAncestors is the number of ancestors in the status of a policy with
+// metrics for their conditions. The CRDs allow 16, but each ancestor needs
+// metrics of its own in the config, see ForEachIndex.
+const MaxAncestors = 4
+
+// MaxRules is the maximum number of rules of a route, the maxItems of
+// spec.rules in the Gateway API CRDs.
+const MaxRules = 16
+
+// MaxDetailedRules is the number of rules of a route with metrics for their
+// backends, matches, filters and timeouts. Each rule needs metrics of its own
+// in the config, see ForEachIndex.
+const MaxDetailedRules = 8
+
+// MaxMatches is the number of matches of a route rule with metrics. The CRDs
+// allow 64, but each match of each rule needs metrics of its own in the
+// config, see ForEachRuleEntry.
+const MaxMatches = 2
+
+// MaxFilters is the number of filters of a route rule with metrics. The CRDs
+// allow 16, but each filter of each rule needs metrics of its own in the
+// config, see ForEachRuleEntry.
+const MaxFilters = 2
+
+// unrolledErrorLogV is the verbosity errors of the metrics of ForEachIndex
+// and of conditions looked up by type are logged at. Objects with fewer
+// entries than the index or without the condition don't resolve the path,
+// which isn't an error worth logging by default.
+const unrolledErrorLogV klog.Level = 4
+
+// Optional returns metric with errors resolving its paths only logged at a
+// higher verbosity, like the metrics of ForEachIndex, for metrics of fields
+// that many objects don't have.
+func Optional(metric customresourcestate.Generator) customresourcestate.Generator {
+	metric.ErrorLogV = unrolledErrorLogV
+	return metric
+}
+
+// Path is a path to a field of an object, e.g. Path{"spec", "parentRefs"}.
+type Path []string
+
+// Labels maps label names to the path of their value.
+type Labels map[string][]string
+
+// Resource returns the config for the kind with the given metrics. Every
+// metric has a name label and, for namespaced kinds, a namespace label.
+func Resource(prefix string, gvk customresourcestate.GroupVersionKind, namespaced bool, metrics ...[]customresourcestate.Generator) customresourcestate.Resource {
+	labels := Labels{"name": {"metadata", "name"}}
+	if namespaced {
+		labels["namespace"] = []string{"metadata", "namespace"}
+	}
+
+	r := customresourcestate.Resource{
+		MetricNamePrefix: &prefix,
+		GroupVersionKind: gvk,
+		Labels:           customresourcestate.Labels{LabelsFromPath: labels},
+	}
+	for _, m := range metrics {
+		r.Metrics = append(r.Metrics, m...)
+	}
+	return r
+}
+
+// Metrics groups metrics for Resource.
+func Metrics(metrics ...customresourcestate.Generator) []customresourcestate.Generator {
+	return metrics
+}
+
+// Info returns an info metric with the labels of each object at path.
+func Info(name, help string, path Path, labels Labels) customresourcestate.Generator {
+	return customresourcestate.Generator{
+		Name: name,
+		Help: help,
+		Each: customresourcestate.Metric{
+			Type: customresourcestate.MetricTypeInfo,
+			Info: &customresourcestate.MetricInfo{
+				MetricMeta: customresourcestate.MetricMeta{Path: path, LabelsFromPath: labels},
+			},
+		},
+	}
+}
+
+// Gauge returns a gauge metric with the value at valueFrom of each object at
+// path. An empty valueFrom uses the value at path itself.
+func Gauge(name, help string, path Path, labels Labels, valueFrom Path) customresourcestate.Generator {
+	return customresourcestate.Generator{
+		Name: name,
+		Help: help,
+		Each: customresourcestate.Metric{
+			Type: customresourcestate.MetricTypeGauge,
+			Gauge: &customresourcestate.MetricGauge{
+				MetricMeta: customresourcestate.MetricMeta{Path: path, LabelsFromPath: labels},
+				ValueFrom:  valueFrom,
+			},
+		},
+	}
+}
+
+// MetadataMetrics returns the labels, created, deleted and
+// metadata_generation metrics every kind has.
+func MetadataMetrics() []customresourcestate.Generator {
+	return Metrics(
+		Info("labels", "Kubernetes labels converted to Prometheus labels.", Path{"metadata"}, Labels{"*": {"labels"}}),
+		Gauge("created", "created timestamp", Path{"metadata", "creationTimestamp"}, nil, nil),
+		Gauge("deleted", "deletion timestamp", Path{"metadata", "deletionTimestamp"}, nil, nil),
+		Gauge("metadata_generation", "Generation of the desired state of the object", Path{"metadata", "generation"}, nil, nil),
+	)
+}
+
+// StatusConditions returns the status metric with a series per condition in
+// status.conditions, set to 1 if the condition status is True.
+func StatusConditions() customresourcestate.Generator {
+	return Gauge("status", "status condition", Path{"status", "conditions"}, Labels{"type": {"type"}, "reason": {"reason"}}, Path{"status"})
+}
+
+// StatusLastTransitionTime returns the status_last_transition_time metric
+// with a series per condition in status.conditions, set to the time the
+// condition status last changed. It is the companion of StatusConditions.
+func StatusLastTransitionTime() customresourcestate.Generator {
+	return Gauge("status_last_transition_time", "Last transition time of the status condition", Path{"status", "conditions"}, Labels{"type": {"type"}}, Path{"lastTransitionTime"})
+}
+
+// StatusObservedGeneration returns the status_condition_observed_generation
+// metric with a series per condition of the given types in status.conditions,
+// set to the generation the condition was set for. The status is stale while
+// it is behind metadata_generation. Conditions that don't record it have no
+// series: they are looked up by type, as kube-state-metrics can't skip the
+// conditions without an observedGeneration in a list.
+func StatusObservedGeneration(conditionTypes ...string) []customresourcestate.Generator {
+	var metrics []customresourcestate.Generator
+	for _, conditionType := range conditionTypes {
+		condition := "[type=" + conditionType + "]"
+		m := Gauge("status_condition_observed_generation", "Generation of the object the status condition was set for", Path{"status", "conditions", condition, "observedGeneration"}, nil, nil)
+		m.Labels.LabelsFromPath = Labels{"type": {"status", "conditions", condition, "type"}}
+		m.ErrorLogV = unrolledErrorLogV
+		metrics = append(metrics, m)
+	}
+	return metrics
+}
+
+// Hostnames returns the hostname_info metric with a series per hostname in
+// spec.hostnames.
+func Hostnames() customresourcestate.Generator {
+	return Info("hostname_info", "Hostname information", Path{"spec", "hostnames"}, Labels{"hostname": {}})
+}
+
+// parentRefLabels returns the labels of a Gateway API ParentReference at
+// path.
+func parentRefLabels(path ...string) Labels {
+	labels := Labels{}
+	for label, field := range map[string]string{
+		"parent_group":        "group",
+		"parent_kind":         "kind",
+		"parent_name":         "name",
+		"parent_namespace":    "namespace",
+		"parent_section_name": "sectionName",
+		"parent_port":         "port",
+	} {
+		labels[label] = append(append([]string{}, path...), field)
+	}
+	return labels
+}
+
+// ParentRefs returns the parent_info metric for the parents a route wants to
+// be attached to, and the status_parent_info metric for the parents in the
+// route status.
+func ParentRefs(kind string) []customresourcestate.Generator {
+	kind = strings.ToLower(kind)
+	statusLabels := parentRefLabels("parentRef")
+	statusLabels["controller_name"] = []string{"controllerName"}
+	return Metrics(
+		Info("parent_info", "Parent references that the "+kind+" wants to be attached to", Path{"spec", "parentRefs"}, parentRefLabels()),
+		Info("status_parent_info", "Parent references that the "+kind+" is attached to", Path{"status", "parents"}, statusLabels),
+	)
+}
+
+// ForEachIndex returns a metric for each index of the list at path, up to
+// maxItems. metric is given the path of the entry at the index.
+//
+// kube-state-metrics can't iterate a list in each entry of another list in
+// one metric, like the conditions of each entry of status.parents. The
+// metrics returned by metric must have the same name and help: they are next
+// to each other in the config, so kube-state-metrics exposes them as one
+// family. Errors resolving the paths of indexes an object doesn't have are
+// only logged at a higher verbosity.
+//
+// Each index is a metric of its own in the config, so maxItems is often lower
+// than the maxItems of the list in the CRD: kubectl apply keeps the config in
+// an annotation of the ConfigMap, which is limited to 256 KiB.
+func ForEachIndex(path Path, maxItems int, metric func(entry func(fields ...string) Path) customresourcestate.Generator) []customresourcestate.Generator {
+	var metrics []customresourcestate.Generator
+	for i := 0; i < maxItems; i++ {
+		index := strconv.Itoa(i)
+		entry := func(fields ...string) Path {
+			return append(append(Path{}, path...), append([]string{index}, fields...)...)
+		}
+		m := metric(entry)
+		m.ErrorLogV = unrolledErrorLogV
+		metrics = append(metrics, m)
+	}
+	return metrics
+}
+
+// WithIndexLabel sets the given label of each metric returned by ForEachIndex
+// to the index of its entry, so entries with the same labels have separate
+// series.
+func WithIndexLabel(label string, metrics []customresourcestate.Generator) []customresourcestate.Generator {
+	for i := range metrics {
+		metrics[i].Labels.CommonLabels = map[string]string{label: strconv.Itoa(i)}
+	}
+	return metrics
+}
+
+// ForEachRule returns a metric for each rule in spec.rules of a route with
+// ForEachIndex, up to maxRules. The rule_index label is the index of the rule.
+func ForEachRule(maxRules int, metric func(rule func(fields ...string) Path) customresourcestate.Generator) []customresourcestate.Generator {
+	return WithIndexLabel("rule_index", ForEachIndex(Path{"spec", "rules"}, maxRules, metric))
+}
+
+// ForEachRuleEntry returns a metric for each index of the list at field in
+// each of the first MaxDetailedRules rules of a route, like the matches of
+// every rule. The rule_index label is the index of the rule and label the
+// index of the entry in the list.
+func ForEachRuleEntry(field string, maxItems int, label string, metric func(entry func(fields ...string) Path) customresourcestate.Generator) []customresourcestate.Generator {
+	var metrics []customresourcestate.Generator
+	for i := 0; i < MaxDetailedRules; i++ {
+		index := strconv.Itoa(i)
+		for _, m := range WithIndexLabel(label, ForEachIndex(Path{"spec", "rules", index, field}, maxItems, metric)) {
+			m.Labels.CommonLabels["rule_index"] = index
+			metrics = append(metrics, m)
+		}
+	}
+	return metrics
+}
+
+// ParentConditions returns the status_parent_condition metric with a series
+// per condition of each parent in the route status, set to 1 if the condition
+// status is True.
+func ParentConditions(kind string) []customresourcestate.Generator {
+	return ForEachIndex(Path{"status", "parents"}, MaxParents, func(parent func(...string) Path) customresourcestate.Generator {
+		m := Gauge(
+			"status_parent_condition",
+			"Status conditions of the parents that the "+strings.ToLower(kind)+" is attached to",
+			parent("conditions"),
+			Labels{"type": {"type"}, "reason": {"reason"}},
+			Path{"status"},
+		)
+		m.Labels.LabelsFromPath = Labels{
+			"parent_name":         parent("parentRef", "name"),
+			"parent_namespace":    parent("parentRef", "namespace"),
+			"parent_section_name": parent("parentRef", "sectionName"),
+		}
+		return m
+	})
+}
+
+// routeConditionTypes are the condition types the status of every route
+// parent has.
+var routeConditionTypes = []string{"Accepted", "ResolvedRefs"}
+
+// ParentObservedGeneration returns the status_condition_observed_generation
+// metric of a route, with a series per Accepted and ResolvedRefs condition of
+// each parent in the route status, set to the generation the condition was
+// set for. Conditions that don't record it have no series.
+//
+// The conditions are looked up by type, so the path of each series ends in
+// observedGeneration: kube-state-metrics would expose a 0 for each missing
+// parent if the value could be nil.
+func ParentObservedGeneration(kind string) []customresourcestate.Generator {
+	var metrics []customresourcestate.Generator
+	for _, conditionType := range routeConditionTypes {
+		condition := "[type=" + conditionType + "]"
+		metrics = append(metrics, ForEachIndex(Path{"status", "parents"}, MaxParents, func(parent func(...string) Path) customresourcestate.Generator {
+			m := Gauge(
+				"status_condition_observed_generation",
+				"Generation of the "+strings.ToLower(kind)+" the status conditions of its parents were set for",
+				parent("conditions", condition, "observedGeneration"),
+				nil,
+				nil,
+			)
+			m.Labels.LabelsFromPath = Labels{
+				"parent_name":         parent("parentRef", "name"),
+				"parent_namespace":    parent("parentRef", "namespace"),
+				"parent_section_name": parent("parentRef", "sectionName"),
+				"type":                parent("conditions", condition, "type"),
+			}
+			return m
+		})...)
+	}
+	return metrics
+}
+
+// policyConditionTypes are the condition types the status of every policy
+// ancestor has.
+var policyConditionTypes = []string{"Accepted"}
+
+// ancestorLabels returns the labels of the ancestor ref and controller of an
+// entry in status.ancestors.
+func ancestorLabels(ancestor func(...string) Path) Labels {
+	return Labels{
+		"ancestor_group":        ancestor("ancestorRef", "group"),
+		"ancestor_kind":         ancestor("ancestorRef", "kind"),
+		"ancestor_name":         ancestor("ancestorRef", "name"),
+		"ancestor_namespace":    ancestor("ancestorRef", "namespace"),
+		"ancestor_section_name": ancestor("ancestorRef", "sectionName"),
+		"controller_name":       ancestor("controllerName"),
+	}
+}
+
+// AncestorConditions returns the status_ancestor_condition metric of a policy
+// with a series per condition of each ancestor in the policy status, set to 1
+// if the condition status is True.
+func AncestorConditions(kind string) []customresourcestate.Generator {
+	return ForEachIndex(Path{"status", "ancestors"}, MaxAncestors, func(ancestor func(...string) Path) customresourcestate.Generator {
+		m := Gauge(
+			"status_ancestor_condition",
+			"Status conditions of the "+strings.ToLower(kind)+" for each of its ancestors",
+			ancestor("conditions"),
+			Labels{"type": {"type"}, "reason": {"reason"}},
+			Path{"status"},
+		)
+		m.Labels.LabelsFromPath = ancestorLabels(ancestor)
+		return m
+	})
+}
+
+// AncestorObservedGeneration returns the status_condition_observed_generation
+// metric of a policy, with a series per Accepted condition of each ancestor in
+// the policy status, like ParentObservedGeneration for routes.
+func AncestorObservedGeneration(kind string) []customresourcestate.Generator {
+	var metrics []customresourcestate.Generator
+	for _, conditionType := range policyConditionTypes {
+		condition := "[type=" + conditionType + "]"
+		metrics = append(metrics, ForEachIndex(Path{"status", "ancestors"}, MaxAncestors, func(ancestor func(...string) Path) customresourcestate.Generator {
+			m := Gauge(
+				"status_condition_observed_generation",
+				"Generation of the "+strings.ToLower(kind)+" the status conditions of its ancestors were set for",
+				ancestor("conditions", condition, "observedGeneration"),
+				nil,
+				nil,
+			)
+			m.Labels.LabelsFromPath = ancestorLabels(ancestor)
+			m.Labels.LabelsFromPath["type"] = ancestor("conditions", condition, "type")
+			return m
+		})...)
+	}
+	return metrics
+}
+
+// Rules returns the rule_info metric with a series per field set in each rule
+// in spec.rules, like matches or backendRefs. kube-state-metrics can't expose
+// the length of a list, so there is no rule count, but every rule with a
+// field has a series, so the rules of a route can be counted by rule_index.
+// Rules without any field have none.
+func Rules(kind string) []customresourcestate.Generator {
+	return ForEachRule(MaxRules, func(rule func(...string) Path) customresourcestate.Generator {
+		m := Info("rule_info", "Fields set in each rule of the "+strings.ToLower(kind)+", a series per field, to count the rules by rule_index", rule(), nil)
+		m.Each.Info.LabelFromKey = "field"
+		return m
+	})
+}
+
+// BackendRefs returns the backend_ref_info metric with a series per backend of
+// the first MaxDetailedRules rules in spec.rules. The rule_index label is the
+// index of the rule.
+func BackendRefs(kind string) []customresourcestate.Generator {
+	return ForEachRule(MaxDetailedRules, func(rule func(...string) Path) customresourcestate.Generator {
+		return Info("backend_ref_info", "Backends that the rules of the "+strings.ToLower(kind)+" send traffic to", rule("backendRefs"), Labels{
+			"backend_group":     {"group"},
+			"backend_kind":      {"kind"},
+			"backend_name":      {"name"},
+			"backend_namespace": {"namespace"},
+			"backend_port":      {"port"},
+			"weight":            {"weight"},
+		})
+	})
+}
+
+// TargetRef returns the target_info metric for the object a policy targets
+// with spec.targetRef.
+func TargetRef(kind string) customresourcestate.Generator {
+	return Info(
+		"target_info",
+		"Target references that the "+strings.ToLower(kind)+" wants to be attached to",
+		Path{"spec", "targetRef"},
+		Labels{
+			"target_group":        {"group"},
+			"target_kind":         {"kind"},
+			"target_name":         {"name"},
+			"target_namespace":    {"namespace"},
+			"target_section_name": {"sectionName"},
+		},
+	)
+}
+
+// TargetRefs returns the target_info metric for the objects a policy targets
+// with spec.targetRefs, local references in the namespace of the policy.
+func TargetRefs(kind string) customresourcestate.Generator {
+	return Info(
+		"target_info",
+		"Target references that the "+strings.ToLower(kind)+" wants to be attached to",
+		Path{"spec", "targetRefs"},
+		Labels{
+			"target_group":        {"group"},
+			"target_kind":         {"kind"},
+			"target_name":         {"name"},
+			"target_section_name": {"sectionName"},
+		},
+	)
+}
+
+// PolicyTargetRefs returns the target_info metric for policy kinds with
+// versions that use spec.targetRef and versions that use spec.targetRefs, with
+// a series for each target in either of them.
+func PolicyTargetRefs(kind string) []customresourcestate.Generator {
+	return Metrics(TargetRef(kind), TargetRefs(kind))
+}
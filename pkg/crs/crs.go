@@ -0,0 +1,143 @@
+// Package crs builds and writes kube-state-metrics CustomResourceState
+// configs.
+//
+// Configs are built from the customresourcestate config types, so they are
+// checked against the same types kube-state-metrics decodes them into. The
+// building blocks in this package cover the metrics most kinds share, such as
+// the metadata metrics, status conditions and references to other objects.
+package crs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
+)
+
+// Kind is the kind of the kube-state-metrics config, which kube-state-metrics
+// itself doesn't check.
+const Kind = "CustomResourceStateMetrics"
+
+type config struct {
+	Kind string                          `yaml:"kind"`
+	Spec customresourcestate.MetricsSpec `yaml:"spec"`
+}
+
+// Write writes a config with the given resources to w in a canonical form:
+// fields with empty or default values are left out and paths are written in
+// flow style. header is written as a comment before the config.
+func Write(w io.Writer, header string, resources ...customresourcestate.Resource) error {
+	doc := &yaml.Node{}
+	if err := doc.Encode(config{Kind: Kind, Spec: customresourcestate.MetricsSpec{Resources: resources}}); err != nil {
+		return err
+	}
+	prune(doc, false)
+	doc.HeadComment = header
+
+	buf := &bytes.Buffer{}
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Read reads the resources of all configs in r. A config can also be only the
+// list of resources, as in a config that is appended to the resources of
+// another config.
+func Read(r io.Reader) ([]customresourcestate.Resource, error) {
+	var resources []customresourcestate.Resource
+	dec := yaml.NewDecoder(r)
+	for {
+		doc := &yaml.Node{}
+		if err := dec.Decode(doc); err != nil {
+			if err == io.EOF {
+				return resources, nil
+			}
+			return nil, err
+		}
+
+		if len(doc.Content) == 1 && doc.Content[0].Kind == yaml.SequenceNode {
+			var list []customresourcestate.Resource
+			if err := doc.Decode(&list); err != nil {
+				return nil, err
+			}
+			resources = append(resources, list...)
+			continue
+		}
+
+		c := config{}
+		if err := doc.Decode(&c); err != nil {
+			return nil, err
+		}
+		if c.Kind != "" && c.Kind != Kind {
+			return nil, fmt.Errorf("unexpected config kind %q, expected %s", c.Kind, Kind)
+		}
+		resources = append(resources, c.Spec.Resources...)
+	}
+}
+
+// prune removes mapping entries with null, empty, false or zero values, moves
+// path before the other fields of a metric and writes sequences of scalars in
+// flow style. Entries of labelsFromPath are kept even if empty, as an empty
+// path means the value itself.
+func prune(n *yaml.Node, keepEmpty bool) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		for _, c := range n.Content {
+			prune(c, false)
+		}
+	case yaml.SequenceNode:
+		flow := true
+		for _, c := range n.Content {
+			prune(c, false)
+			if c.Kind != yaml.ScalarNode {
+				flow = false
+			}
+		}
+		if flow {
+			n.Style = yaml.FlowStyle
+		}
+	case yaml.MappingNode:
+		var content []*yaml.Node
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i], n.Content[i+1]
+			prune(value, key.Value == "labelsFromPath")
+			if !keepEmpty && empty(value) {
+				continue
+			}
+			if key.Value == "path" && !keepEmpty {
+				content = append([]*yaml.Node{key, value}, content...)
+				continue
+			}
+			content = append(content, key, value)
+		}
+		n.Content = content
+	}
+}
+
+func empty(n *yaml.Node) bool {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		switch n.Tag {
+		case "!!null":
+			return true
+		case "!!str":
+			return n.Value == ""
+		case "!!bool":
+			return n.Value == "false"
+		case "!!int":
+			return n.Value == "0"
+		}
+	case yaml.SequenceNode, yaml.MappingNode:
+		return len(n.Content) == 0
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package crs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
+)
+
+func testResource() customresourcestate.Resource {
+	return Resource("gatewayapi_httproute", customresourcestate.GroupVersionKind{
+		Group:   "gateway.networking.k8s.io",
+		Version: "v1beta1",
+		Kind:    "HTTPRoute",
+	}, true,
+		MetadataMetrics(),
+		Metrics(Hostnames(), StatusConditions()),
+	)
+}
+
+func TestWrite(t *testing.T) {
+	out := &strings.Builder{}
+	if err := Write(out, "Generated for a test.", testResource()); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	expected := `# Generated for a test.
+kind: CustomResourceStateMetrics
+spec:
+  resources:
+    - metricNamePrefix: gatewayapi_httproute
+      groupVersionKind:
+        group: gateway.networking.k8s.io
+        version: v1beta1
+        kind: HTTPRoute
+      labelsFromPath:
+        name: [metadata, name]
+        namespace: [metadata, namespace]
+      metrics:
+        - name: labels
+          help: Kubernetes labels converted to Prometheus labels.
+          each:
+            type: Info
+            info:
+              path: [metadata]
+              labelsFromPath:
+                '*': [labels]
+        - name: created
+          help: created timestamp
+          each:
+            type: Gauge
+            gauge:
+              path: [metadata, creationTimestamp]
+        - name: deleted
+          help: deletion timestamp
+          each:
+            type: Gauge
+            gauge:
+              path: [metadata, deletionTimestamp]
+        - name: metadata_generation
+          help: Generation of the desired state of the object
+          each:
+            type: Gauge
+            gauge:
+              path: [metadata, generation]
+        - name: hostname_info
+          help: Hostname information
+          each:
+            type: Info
+            info:
+              path: [spec, hostnames]
+              labelsFromPath:
+                hostname: []
+        - name: status
+          help: status condition
+          each:
+            type: Gauge
+            gauge:
+              path: [status, conditions]
+              labelsFromPath:
+                reason: [reason]
+                type: [type]
+              valueFrom: [status]
+`
+	if diff := cmp.Diff(strings.Split(expected, "\n"), strings.Split(out.String(), "\n")); diff != "" {
+		t.Fatalf("unexpected config (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestReadWritten(t *testing.T) {
+	out := &strings.Builder{}
+	if err := Write(out, "", testResource()); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	resources, err := Read(strings.NewReader(out.String()))
+	if err != nil {
+		t.Fatalf("failed to read: %v", err)
+	}
+	if len(resources) != 1 {
+		t.Fatalf("expected 1 resource, got %d", len(resources))
+	}
+	// the written config leaves out empty fields, which are read back as nil
+	if diff := cmp.Diff(testResource(), resources[0]); diff != "" {
+		t.Fatalf("unexpected resource (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestReadResourceList(t *testing.T) {
+	resources, err := Read(strings.NewReader(`
+    - groupVersionKind:
+        group: kuadrant.io
+        kind: "TLSPolicy"
+        version: "v1"
+      metricNamePrefix: gatewayapi_tlspolicy
+      metrics:
+      - name: "created"
+        help: "created timestamp"
+        each:
+          type: Gauge
+          gauge:
+            path: [metadata, creationTimestamp]
+`))
+	if err != nil {
+		t.Fatalf("failed to read: %v", err)
+	}
+	if len(resources) != 1 || resources[0].GetMetricNamePrefix() != "gatewayapi_tlspolicy" || len(resources[0].Metrics) != 1 {
+		t.Fatalf("unexpected resources: %+v", resources)
+	}
+}
+
+func TestReadWrongKind(t *testing.T) {
+	if _, err := Read(strings.NewReader("kind: ConfigMap\n")); err == nil {
+		t.Fatal("expected an error reading a config of the wrong kind")
+	}
+}
@@ -0,0 +1,205 @@
+package crs
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
+)
+
+// WriteMarkdown writes a reference of the metrics of the given resources to w
+// as Markdown: a section per resource with the labels every metric of the
+// resource has, and a table of its metrics with their type, help text, path
+// and labels. Metrics that kube-state-metrics exposes as one family are
+// written as one row.
+func WriteMarkdown(w io.Writer, resources ...customresourcestate.Resource) error {
+	b := &strings.Builder{}
+	for _, r := range resources {
+		gvk := r.GroupVersionKind
+		fmt.Fprintf(b, "### %s\n\n", gvk.Kind)
+		fmt.Fprintf(b, "Group `%s`, version `%s`.\n\n", gvk.Group, gvk.Version)
+
+		if labels := labelRows(r.Labels); len(labels) > 0 {
+			b.WriteString("Labels of every metric:\n\n")
+			b.WriteString("| Label | Path |\n")
+			b.WriteString("| --- | --- |\n")
+			for _, l := range labels {
+				fmt.Fprintf(b, "| `%s` | %s |\n", l.name, l.value)
+			}
+			b.WriteString("\n")
+		}
+
+		b.WriteString("| Metric | Type | Help | Path | Value | Labels |\n")
+		b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+		for i, m := range r.Metrics {
+			// consecutive metrics with the same name and help are one family,
+			// written as a single row with the range of their paths, or all
+			// of them if the metrics aren't unrolled
+			if i > 0 && r.Metrics[i-1].Name == m.Name && r.Metrics[i-1].Help == m.Help {
+				continue
+			}
+			path, value, labels := metricColumns(m)
+			last := i
+			for last+1 < len(r.Metrics) && r.Metrics[last+1].Name == m.Name && r.Metrics[last+1].Help == m.Help {
+				last++
+			}
+			if last > i && m.ErrorLogV == 0 {
+				for _, other := range r.Metrics[i+1 : last+1] {
+					otherPath, _, _ := metricColumns(other)
+					path += "<br>" + otherPath
+				}
+			} else if last > i {
+				lastPath, _, _ := metricColumns(r.Metrics[last])
+				path += " … " + lastPath
+			}
+			var cells []string
+			for _, l := range familyCommonLabels(r.Metrics[i : last+1]) {
+				cells = append(cells, fmt.Sprintf("`%s`: %s", l.name, l.value))
+			}
+			labelsFromPath := labelRows(customresourcestate.Labels{LabelsFromPath: m.Labels.LabelsFromPath})
+			if last > i && m.ErrorLogV != 0 {
+				// the labels of unrolled metrics are read from the entry
+				// of each metric, like their paths
+				for j, l := range labelRows(customresourcestate.Labels{LabelsFromPath: r.Metrics[last].Labels.LabelsFromPath}) {
+					if j < len(labelsFromPath) && labelsFromPath[j].name == l.name && labelsFromPath[j].value != l.value {
+						labelsFromPath[j].value += " … " + l.value
+					}
+				}
+			}
+			for _, l := range labelsFromPath {
+				cells = append(cells, fmt.Sprintf("`%s`: %s", l.name, l.value))
+			}
+			for _, l := range labels {
+				cells = append(cells, fmt.Sprintf("`%s`: %s", l.name, l.value))
+			}
+			fmt.Fprintf(b, "| `%s` | %s | %s | %s | %s | %s |\n",
+				FamilyName(r, m), m.Each.Type, cell(m.Help), path, value, strings.Join(cells, "<br>"))
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type labelRow struct {
+	name  string
+	value string
+}
+
+// labelRows returns the common labels and the labels from path, sorted by
+// name.
+func labelRows(labels customresourcestate.Labels) []labelRow {
+	var rows []labelRow
+	for name, value := range labels.CommonLabels {
+		rows = append(rows, labelRow{name, fmt.Sprintf("%q", value)})
+	}
+	for name, path := range labels.LabelsFromPath {
+		rows = append(rows, labelRow{name, jsonPath(path)})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].name < rows[j].name })
+	return rows
+}
+
+// familyCommonLabels returns the common labels of the metrics of a family,
+// sorted by name. Labels with more than two values, such as the index of
+// WithIndexLabel, are written as the range of their values, others with all
+// of them.
+func familyCommonLabels(metrics []customresourcestate.Generator) []labelRow {
+	var names []string
+	values := map[string][]string{}
+	for _, m := range metrics {
+		for name, value := range m.Labels.CommonLabels {
+			if _, ok := values[name]; !ok {
+				names = append(names, name)
+			}
+			if !contains(values[name], value) {
+				values[name] = append(values[name], value)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	var rows []labelRow
+	for _, name := range names {
+		v := values[name]
+		var quoted []string
+		for _, value := range v {
+			quoted = append(quoted, fmt.Sprintf("%q", value))
+		}
+		value := strings.Join(quoted, ", ")
+		if len(v) > 2 {
+			value = quoted[0] + " … " + quoted[len(quoted)-1]
+		}
+		rows = append(rows, labelRow{name, value})
+	}
+	return rows
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// metricColumns returns the path, value and labels of a metric. The value
+// and label paths are relative to the path.
+func metricColumns(m customresourcestate.Generator) (path, value string, labels []labelRow) {
+	var meta customresourcestate.MetricMeta
+	var labelFromKey string
+	switch {
+	case m.Each.Gauge != nil:
+		meta, labelFromKey = m.Each.Gauge.MetricMeta, m.Each.Gauge.LabelFromKey
+		value = jsonPath(m.Each.Gauge.ValueFrom)
+		if m.Each.Gauge.NilIsZero {
+			value += ", 0 if nil"
+		}
+	case m.Each.Info != nil:
+		meta, labelFromKey = m.Each.Info.MetricMeta, m.Each.Info.LabelFromKey
+		value = "1"
+	case m.Each.StateSet != nil:
+		meta = m.Each.StateSet.MetricMeta
+		value = fmt.Sprintf("1 if %s is the `%s` label", jsonPath(m.Each.StateSet.ValueFrom), m.Each.StateSet.LabelName)
+		labels = append(labels, labelRow{m.Each.StateSet.LabelName, "one of " + code(m.Each.StateSet.List)})
+	}
+
+	labels = append(labels, labelRows(customresourcestate.Labels{LabelsFromPath: meta.LabelsFromPath})...)
+	if labelFromKey != "" {
+		labels = append(labels, labelRow{labelFromKey, "key of each entry"})
+	}
+	return jsonPath(meta.Path), value, labels
+}
+
+// jsonPath formats a path like `.spec.listeners`, or `.` for the empty path,
+// which refers to the value itself.
+func jsonPath(path []string) string {
+	p := ""
+	for _, elem := range path {
+		if strings.HasPrefix(elem, "[") {
+			p += elem
+			continue
+		}
+		p += "." + elem
+	}
+	if p == "" {
+		p = "."
+	}
+	return "`" + p + "`"
+}
+
+func code(values []string) string {
+	var quoted []string
+	for _, v := range values {
+		quoted = append(quoted, "`"+v+"`")
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// cell escapes text for a table cell.
+func cell(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "|", `\|`), "\n", " ")
+}
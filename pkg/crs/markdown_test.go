@@ -0,0 +1,144 @@
+package crs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	r := testResource()
+	r.Metrics = append(r.Metrics, customresourcestate.Generator{
+		Name: "phase",
+		Help: "Route phase | state",
+		Each: customresourcestate.Metric{
+			Type: customresourcestate.MetricTypeStateSet,
+			StateSet: &customresourcestate.MetricStateSet{
+				MetricMeta: customresourcestate.MetricMeta{Path: Path{"status"}},
+				List:       []string{"Pending", "Ready"},
+				LabelName:  "phase",
+				ValueFrom:  Path{"phase"},
+			},
+		},
+	})
+
+	out := &strings.Builder{}
+	if err := WriteMarkdown(out, r); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	expected := "### HTTPRoute\n" +
+		"\n" +
+		"Group `gateway.networking.k8s.io`, version `v1beta1`.\n" +
+		"\n" +
+		"Labels of every metric:\n" +
+		"\n" +
+		"| Label | Path |\n" +
+		"| --- | --- |\n" +
+		"| `name` | `.metadata.name` |\n" +
+		"| `namespace` | `.metadata.namespace` |\n" +
+		"\n" +
+		"| Metric | Type | Help | Path | Value | Labels |\n" +
+		"| --- | --- | --- | --- | --- | --- |\n" +
+		"| `gatewayapi_httproute_labels` | Info | Kubernetes labels converted to Prometheus labels. | `.metadata` | 1 | `*`: `.labels` |\n" +
+		"| `gatewayapi_httproute_created` | Gauge | created timestamp | `.metadata.creationTimestamp` | `.` |  |\n" +
+		"| `gatewayapi_httproute_deleted` | Gauge | deletion timestamp | `.metadata.deletionTimestamp` | `.` |  |\n" +
+		"| `gatewayapi_httproute_metadata_generation` | Gauge | Generation of the desired state of the object | `.metadata.generation` | `.` |  |\n" +
+		"| `gatewayapi_httproute_hostname_info` | Info | Hostname information | `.spec.hostnames` | 1 | `hostname`: `.` |\n" +
+		"| `gatewayapi_httproute_status` | Gauge | status condition | `.status.conditions` | `.status` | `reason`: `.reason`<br>`type`: `.type` |\n" +
+		"| `gatewayapi_httproute_phase` | StateSet | Route phase \\| state | `.status` | 1 if `.phase` is the `phase` label | `phase`: one of `Pending`, `Ready` |\n" +
+		"\n"
+	if diff := cmp.Diff(strings.Split(expected, "\n"), strings.Split(out.String(), "\n")); diff != "" {
+		t.Fatalf("unexpected markdown (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestWriteMarkdownUnrolled(t *testing.T) {
+	r := Resource("gatewayapi_httproute", customresourcestate.GroupVersionKind{
+		Group:   "gateway.networking.k8s.io",
+		Version: "v1beta1",
+		Kind:    "HTTPRoute",
+	}, false, BackendRefs("HTTPRoute"))
+
+	out := &strings.Builder{}
+	if err := WriteMarkdown(out, r); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	row := "| `gatewayapi_httproute_backend_ref_info` | Info | Backends that the rules of the httproute send traffic to | " +
+		"`.spec.rules.0.backendRefs` … `.spec.rules.7.backendRefs` | 1 | " +
+		"`rule_index`: \"0\" … \"7\"<br>`backend_group`: `.group`<br>`backend_kind`: `.kind`<br>`backend_name`: `.name`<br>" +
+		"`backend_namespace`: `.namespace`<br>`backend_port`: `.port`<br>`weight`: `.weight` |\n"
+	if !strings.Contains(out.String(), row) {
+		t.Fatalf("expected the unrolled metrics as one row:\n%s\ngot:\n%s", row, out.String())
+	}
+}
+
+func TestWriteMarkdownUnrolledLabels(t *testing.T) {
+	targets := ForEachIndex(Path{"spec", "endpoints"}, 16, func(endpoint func(...string) Path) customresourcestate.Generator {
+		m := Info("endpoint_target_info", "Targets of the first 16 endpoints", endpoint("targets"), Labels{"target": {}})
+		m.Labels.LabelsFromPath = Labels{"dns_name": endpoint("dnsName")}
+		return m
+	})
+	r := Resource("kuadrant_dnsrecord", customresourcestate.GroupVersionKind{
+		Group:   "kuadrant.io",
+		Version: "v1alpha1",
+		Kind:    "DNSRecord",
+	}, false, WithIndexLabel("endpoint_index", targets))
+
+	out := &strings.Builder{}
+	if err := WriteMarkdown(out, r); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	row := "| `kuadrant_dnsrecord_endpoint_target_info` | Info | Targets of the first 16 endpoints | " +
+		"`.spec.endpoints.0.targets` … `.spec.endpoints.15.targets` | 1 | " +
+		"`endpoint_index`: \"0\" … \"15\"<br>`dns_name`: `.spec.endpoints.0.dnsName` … `.spec.endpoints.15.dnsName`<br>`target`: `.` |\n"
+	if !strings.Contains(out.String(), row) {
+		t.Fatalf("expected the range of the label paths:\n%s\ngot:\n%s", row, out.String())
+	}
+}
+
+func TestWriteMarkdownPolicyTargetRefs(t *testing.T) {
+	r := Resource("gatewayapi_backendtlspolicy", customresourcestate.GroupVersionKind{
+		Group:   "gateway.networking.k8s.io",
+		Version: "v1alpha3",
+		Kind:    "BackendTLSPolicy",
+	}, false, PolicyTargetRefs("BackendTLSPolicy"))
+
+	out := &strings.Builder{}
+	if err := WriteMarkdown(out, r); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	row := "| `gatewayapi_backendtlspolicy_target_info` | Info | Target references that the backendtlspolicy wants to be attached to | " +
+		"`.spec.targetRef`<br>`.spec.targetRefs` | 1 | "
+	if !strings.Contains(out.String(), row) {
+		t.Fatalf("expected both paths in one row:\n%s\ngot:\n%s", row, out.String())
+	}
+}
+
+func TestWriteMarkdownFamilyCommonLabels(t *testing.T) {
+	defaults := Info("limit_info", "Limits", Path{"spec", "defaults", "limits"}, nil)
+	defaults.Labels.CommonLabels = map[string]string{"strategy": "defaults"}
+	overrides := Info("limit_info", "Limits", Path{"spec", "overrides", "limits"}, nil)
+	overrides.Labels.CommonLabels = map[string]string{"strategy": "overrides"}
+	r := Resource("gatewayapi_ratelimitpolicy", customresourcestate.GroupVersionKind{
+		Group:   "kuadrant.io",
+		Version: "v1",
+		Kind:    "RateLimitPolicy",
+	}, false, Metrics(Info("limit_info", "Limits", Path{"spec", "limits"}, nil), defaults, overrides))
+
+	out := &strings.Builder{}
+	if err := WriteMarkdown(out, r); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	row := "| `gatewayapi_ratelimitpolicy_limit_info` | Info | Limits | " +
+		"`.spec.limits`<br>`.spec.defaults.limits`<br>`.spec.overrides.limits` | 1 | `strategy`: \"defaults\", \"overrides\" |\n"
+	if !strings.Contains(out.String(), row) {
+		t.Fatalf("expected the common labels of all metrics of the family:\n%s\ngot:\n%s", row, out.String())
+	}
+}
@@ -0,0 +1,64 @@
+package crs
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
+)
+
+// Merge returns the resources of all configs, in order. Each kind can only be
+// configured once, and resources can't generate metrics with the same name,
+// as kube-state-metrics would expose the same metric family twice. Only
+// consecutive metrics of a resource with the same name and help can share a
+// family.
+func Merge(configs ...[]customresourcestate.Resource) ([]customresourcestate.Resource, error) {
+	var merged []customresourcestate.Resource
+	gvks := map[customresourcestate.GroupVersionKind]bool{}
+	prefixes := map[string]string{}
+	families := map[string]string{}
+
+	for _, resources := range configs {
+		for _, r := range resources {
+			gvk := r.GroupVersionKind
+			if gvks[gvk] {
+				return nil, fmt.Errorf("%s/%s %s is configured more than once", gvk.Group, gvk.Version, gvk.Kind)
+			}
+			gvks[gvk] = true
+
+			// resources without a prefix only collide if their metrics do
+			if prefix := r.GetMetricNamePrefix(); prefix != "" {
+				if other, ok := prefixes[prefix]; ok {
+					return nil, fmt.Errorf("metricNamePrefix %s of %s is already used by %s", prefix, gvk.Kind, other)
+				}
+				prefixes[prefix] = gvk.Kind
+			}
+
+			for i, m := range r.Metrics {
+				family := FamilyName(r, m)
+				// consecutive metrics with the same name and help are exposed
+				// as one family, as ForEachIndex relies on
+				if i > 0 && r.Metrics[i-1].Name == m.Name && r.Metrics[i-1].Help == m.Help {
+					continue
+				}
+				if other, ok := families[family]; ok {
+					return nil, fmt.Errorf("metric %s of %s is already generated by %s", family, gvk.Kind, other)
+				}
+				families[family] = gvk.Kind
+			}
+
+			merged = append(merged, r)
+		}
+	}
+	return merged, nil
+}
+
+// FamilyName returns the name of the metric family kube-state-metrics
+// generates for a metric of a resource.
+func FamilyName(r customresourcestate.Resource, m customresourcestate.Generator) string {
+	var parts []string
+	if prefix := r.GetMetricNamePrefix(); prefix != "" {
+		parts = append(parts, prefix)
+	}
+	return strings.Join(append(parts, m.Name), "_")
+}
@@ -0,0 +1,116 @@
+package crs
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
+)
+
+func policy(prefix, kind string, metrics ...customresourcestate.Generator) customresourcestate.Resource {
+	return Resource(prefix, customresourcestate.GroupVersionKind{Group: "kuadrant.io", Version: "v1", Kind: kind}, true, metrics)
+}
+
+func TestMerge(t *testing.T) {
+	base := []customresourcestate.Resource{testResource()}
+	addOn := []customresourcestate.Resource{
+		policy("gatewayapi_tlspolicy", "TLSPolicy", StatusConditions()),
+		policy("gatewayapi_dnspolicy", "DNSPolicy", StatusConditions()),
+	}
+
+	merged, err := Merge(base, addOn)
+	if err != nil {
+		t.Fatalf("failed to merge: %v", err)
+	}
+	var kinds []string
+	for _, r := range merged {
+		kinds = append(kinds, r.GroupVersionKind.Kind)
+	}
+	if strings.Join(kinds, ",") != "HTTPRoute,TLSPolicy,DNSPolicy" {
+		t.Fatalf("unexpected merged kinds %v", kinds)
+	}
+}
+
+func TestMergeConsecutiveMetrics(t *testing.T) {
+	route := Resource("gatewayapi_httproute", customresourcestate.GroupVersionKind{
+		Group:   "gateway.networking.k8s.io",
+		Version: "v1beta1",
+		Kind:    "HTTPRoute",
+	}, true, ParentRefs("HTTPRoute"), ParentConditions("HTTPRoute"))
+
+	merged, err := Merge([]customresourcestate.Resource{route})
+	if err != nil {
+		t.Fatalf("failed to merge: %v", err)
+	}
+	if len(merged[0].Metrics) != 2+MaxParents {
+		t.Fatalf("expected %d metrics, got %d", 2+MaxParents, len(merged[0].Metrics))
+	}
+}
+
+func TestMergeErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		resources []customresourcestate.Resource
+		expected  string
+	}{
+		{
+			name: "duplicate kind",
+			resources: []customresourcestate.Resource{
+				policy("gatewayapi_tlspolicy", "TLSPolicy", StatusConditions()),
+				policy("kuadrant_tlspolicy", "TLSPolicy", StatusConditions()),
+			},
+			expected: "kuadrant.io/v1 TLSPolicy is configured more than once",
+		},
+		{
+			name: "prefix collision",
+			resources: []customresourcestate.Resource{
+				policy("gatewayapi_policy", "TLSPolicy", StatusConditions()),
+				policy("gatewayapi_policy", "DNSPolicy", TargetRef("DNSPolicy")),
+			},
+			expected: "metricNamePrefix gatewayapi_policy of DNSPolicy is already used by TLSPolicy",
+		},
+		{
+			name: "metric collision",
+			resources: []customresourcestate.Resource{
+				policy("gatewayapi_tlspolicy", "TLSPolicy", Info("target_info", "", nil, nil)),
+				policy("gatewayapi_tlspolicy_target", "DNSPolicy", Info("info", "", nil, nil)),
+			},
+			expected: "metric gatewayapi_tlspolicy_target_info of DNSPolicy is already generated by TLSPolicy",
+		},
+		{
+			name: "metric repeated in a resource",
+			resources: []customresourcestate.Resource{
+				policy("gatewayapi_tlspolicy", "TLSPolicy", StatusConditions(), TargetRef("TLSPolicy"), StatusConditions()),
+			},
+			expected: "metric gatewayapi_tlspolicy_status of TLSPolicy is already generated by TLSPolicy",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Merge(tc.resources[:1], tc.resources[1:])
+			if err == nil || err.Error() != tc.expected {
+				t.Fatalf("expected error %q, got %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestReadMultipleDocuments(t *testing.T) {
+	resources, err := Read(strings.NewReader(`kind: CustomResourceStateMetrics
+spec:
+  resources:
+    - metricNamePrefix: gatewayapi_tlspolicy
+      groupVersionKind: {group: kuadrant.io, version: v1, kind: TLSPolicy}
+---
+kind: CustomResourceStateMetrics
+spec:
+  resources:
+    - metricNamePrefix: gatewayapi_dnspolicy
+      groupVersionKind: {group: kuadrant.io, version: v1, kind: DNSPolicy}
+`))
+	if err != nil {
+		t.Fatalf("failed to read: %v", err)
+	}
+	if len(resources) != 2 || resources[1].GroupVersionKind.Kind != "DNSPolicy" {
+		t.Fatalf("unexpected resources: %+v", resources)
+	}
+}
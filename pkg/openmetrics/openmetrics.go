@@ -0,0 +1,155 @@
+// Package openmetrics parses the OpenMetrics and Prometheus text exposition
+// formats, as served by kube-state-metrics.
+//
+// The parser from github.com/prometheus/common/expfmt only accepts the
+// Prometheus text format and fails on the info and stateset types that
+// CustomResourceState metrics are exposed as, so this package is used instead.
+package openmetrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MetricType is the type of a metric family, as declared by its TYPE line.
+type MetricType string
+
+// Metric types of the OpenMetrics and Prometheus text formats.
+const (
+	Counter        MetricType = "counter"
+	Gauge          MetricType = "gauge"
+	Histogram      MetricType = "histogram"
+	GaugeHistogram MetricType = "gaugehistogram"
+	Summary        MetricType = "summary"
+	Info           MetricType = "info"
+	StateSet       MetricType = "stateset"
+	Unknown        MetricType = "unknown"
+	// Untyped is the Prometheus text format equivalent of Unknown.
+	Untyped MetricType = "untyped"
+)
+
+// sampleSuffixes are the suffixes that samples of a family may add to the
+// family name, by type.
+var sampleSuffixes = map[MetricType][]string{
+	Counter:        {"_total", "_created"},
+	Histogram:      {"_bucket", "_count", "_sum", "_created"},
+	GaugeHistogram: {"_bucket", "_gcount", "_gsum"},
+	Summary:        {"_count", "_sum", "_created"},
+	Info:           {"_info"},
+}
+
+// MetricFamily is a set of samples sharing a name, help text and type.
+type MetricFamily struct {
+	Name    string
+	Help    string
+	Type    MetricType
+	Unit    string
+	Samples []Sample
+}
+
+// Sample is a single series and its value.
+type Sample struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+	// Timestamp is the optional timestamp of the sample, or nil.
+	Timestamp *float64
+}
+
+// Series returns the name and labels of the sample, with labels sorted by
+// name, e.g. `gatewayapi_gateway_status{name="gw",type="Accepted"}`.
+func (s Sample) Series() string {
+	b := &strings.Builder{}
+	b.WriteString(s.Name)
+	if len(s.Labels) == 0 {
+		return b.String()
+	}
+
+	names := make([]string, 0, len(s.Labels))
+	for name := range s.Labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(name)
+		b.WriteString(`="`)
+		b.WriteString(labelValueEscaper.Replace(s.Labels[name]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// String returns the sample in the text exposition format.
+func (s Sample) String() string {
+	str := s.Series() + " " + FormatValue(s.Value)
+	if s.Timestamp != nil {
+		str += " " + FormatValue(*s.Timestamp)
+	}
+	return str
+}
+
+// FormatValue formats a sample value the way kube-state-metrics does.
+func FormatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// owns reports whether a sample named name belongs to the family.
+func (f *MetricFamily) owns(name string) bool {
+	if name == f.Name {
+		return true
+	}
+	for _, suffix := range sampleSuffixes[f.Type] {
+		if name == f.Name+suffix {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+// Write writes the families to w in the text exposition format, with a HELP
+// and TYPE line for each family followed by its samples.
+func Write(w io.Writer, families ...*MetricFamily) error {
+	for _, f := range families {
+		b := &strings.Builder{}
+		if f.Help != "" {
+			fmt.Fprintf(b, "# HELP %s %s\n", f.Name, helpEscaper.Replace(f.Help))
+		}
+		if f.Type != "" {
+			fmt.Fprintf(b, "# TYPE %s %s\n", f.Name, f.Type)
+		}
+		if f.Unit != "" {
+			fmt.Fprintf(b, "# UNIT %s %s\n", f.Name, f.Unit)
+		}
+		for _, s := range f.Samples {
+			b.WriteString(s.String())
+			b.WriteByte('\n')
+		}
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
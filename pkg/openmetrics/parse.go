@@ -0,0 +1,286 @@
+package openmetrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Parse reads all metric families from r, in the order they first appear.
+//
+// Samples are assigned to the family declared by the preceding HELP, TYPE or
+// UNIT line if their name matches it, and otherwise to a family of type
+// Unknown. Families without any samples, such as the ones kube-state-metrics
+// writes headers for when no objects exist, are kept.
+func Parse(r io.Reader) ([]*MetricFamily, error) {
+	p := &parser{byName: map[string]*MetricFamily{}}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		p.line++
+		if err := p.parseLine(scanner.Text()); err != nil {
+			return nil, fmt.Errorf("line %d: %w", p.line, err)
+		}
+		if p.eof {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return p.families, nil
+}
+
+// ParseString is like Parse but reads from a string.
+func ParseString(s string) ([]*MetricFamily, error) {
+	return Parse(strings.NewReader(s))
+}
+
+type parser struct {
+	line     int
+	eof      bool
+	current  *MetricFamily
+	families []*MetricFamily
+	byName   map[string]*MetricFamily
+}
+
+func (p *parser) family(name string) *MetricFamily {
+	f, ok := p.byName[name]
+	if !ok {
+		f = &MetricFamily{Name: name, Type: Unknown}
+		p.byName[name] = f
+		p.families = append(p.families, f)
+	}
+	return f
+}
+
+func (p *parser) parseLine(line string) error {
+	line = strings.TrimRight(line, "\r")
+	if strings.TrimSpace(line) == "" {
+		return nil
+	}
+	if strings.HasPrefix(line, "#") {
+		return p.parseComment(line)
+	}
+
+	s, err := parseSample(line)
+	if err != nil {
+		return err
+	}
+	if p.current == nil || !p.current.owns(s.Name) {
+		p.current = p.family(s.Name)
+	}
+	p.current.Samples = append(p.current.Samples, s)
+	return nil
+}
+
+func (p *parser) parseComment(line string) error {
+	if line == "# EOF" {
+		p.eof = true
+		return nil
+	}
+
+	fields := strings.SplitN(line, " ", 4)
+	if len(fields) < 3 || fields[0] != "#" {
+		// a plain comment
+		return nil
+	}
+	keyword, name := fields[1], fields[2]
+	rest := ""
+	if len(fields) == 4 {
+		rest = fields[3]
+	}
+
+	switch keyword {
+	case "HELP":
+		p.current = p.family(name)
+		p.current.Help = unescape(rest)
+	case "TYPE":
+		t := MetricType(strings.TrimSpace(rest))
+		switch t {
+		case Counter, Gauge, Histogram, GaugeHistogram, Summary, Info, StateSet, Unknown, Untyped:
+		default:
+			return fmt.Errorf("TYPE %s: unknown metric type %q", name, t)
+		}
+		p.current = p.family(name)
+		p.current.Type = t
+	case "UNIT":
+		p.current = p.family(name)
+		p.current.Unit = strings.TrimSpace(rest)
+	}
+	return nil
+}
+
+// parseSample parses a line of the form
+//
+//	name{label="value",...} value [timestamp] [# exemplar]
+func parseSample(line string) (Sample, error) {
+	s := Sample{Labels: map[string]string{}}
+
+	i := 0
+	for i < len(line) && isNameChar(line[i], i == 0) {
+		i++
+	}
+	if i == 0 {
+		return s, fmt.Errorf("invalid metric name in %q", line)
+	}
+	s.Name = line[:i]
+
+	if i < len(line) && line[i] == '{' {
+		n, err := parseLabels(line[i+1:], s.Labels)
+		if err != nil {
+			return s, fmt.Errorf("%s: %w", s.Name, err)
+		}
+		i += n + 1
+	}
+
+	rest := line[i:]
+	if exemplar := strings.Index(rest, " # "); exemplar >= 0 {
+		rest = rest[:exemplar]
+	}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 || len(fields) > 2 {
+		return s, fmt.Errorf("%s: expected a value and an optional timestamp, got %q", s.Name, rest)
+	}
+
+	v, err := parseValue(fields[0])
+	if err != nil {
+		return s, fmt.Errorf("%s: invalid value: %w", s.Name, err)
+	}
+	s.Value = v
+	if len(fields) == 2 {
+		ts, err := parseValue(fields[1])
+		if err != nil {
+			return s, fmt.Errorf("%s: invalid timestamp: %w", s.Name, err)
+		}
+		s.Timestamp = &ts
+	}
+	return s, nil
+}
+
+// parseLabels parses the labels in s, which starts after the opening brace,
+// into labels and returns the number of bytes read including the closing
+// brace.
+func parseLabels(s string, labels map[string]string) (int, error) {
+	i := 0
+	for {
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		if i < len(s) && s[i] == '}' {
+			return i + 1, nil
+		}
+
+		start := i
+		for i < len(s) && isNameChar(s[i], i == start) && s[i] != ':' {
+			i++
+		}
+		if i == start {
+			return 0, fmt.Errorf("invalid label name at %q", s[start:])
+		}
+		name := s[start:i]
+		if i+1 >= len(s) || s[i] != '=' || s[i+1] != '"' {
+			return 0, fmt.Errorf("expected =\" after label %s", name)
+		}
+		i += 2
+
+		value := &strings.Builder{}
+		for {
+			if i >= len(s) {
+				return 0, fmt.Errorf("unterminated value for label %s", name)
+			}
+			c := s[i]
+			if c == '"' {
+				i++
+				break
+			}
+			if c == '\\' {
+				if i+1 >= len(s) {
+					return 0, fmt.Errorf("unterminated escape in label %s", name)
+				}
+				switch s[i+1] {
+				case '\\':
+					value.WriteByte('\\')
+				case '"':
+					value.WriteByte('"')
+				case 'n':
+					value.WriteByte('\n')
+				default:
+					return 0, fmt.Errorf("invalid escape \\%c in label %s", s[i+1], name)
+				}
+				i += 2
+				continue
+			}
+			value.WriteByte(c)
+			i++
+		}
+		if _, ok := labels[name]; ok {
+			return 0, fmt.Errorf("duplicate label %s", name)
+		}
+		labels[name] = value.String()
+
+		switch {
+		case i < len(s) && s[i] == ',':
+			i++
+		case i < len(s) && s[i] == '}':
+			return i + 1, nil
+		default:
+			return 0, fmt.Errorf("expected , or } after label %s", name)
+		}
+	}
+}
+
+func parseValue(s string) (float64, error) {
+	switch s {
+	case "+Inf", "Inf":
+		return math.Inf(1), nil
+	case "-Inf":
+		return math.Inf(-1), nil
+	case "NaN":
+		return math.NaN(), nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// unescape reverses the escaping of HELP text, where only backslashes and
+// newlines are escaped. OpenMetrics also allows escaped double quotes. Other
+// backslashes are kept as is.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	b := &strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case '\\':
+			b.WriteByte('\\')
+		case 'n':
+			b.WriteByte('\n')
+		case '"':
+			b.WriteByte('"')
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+func isNameChar(c byte, first bool) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_', c == ':':
+		return true
+	case c >= '0' && c <= '9':
+		return !first
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package openmetrics
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse(t *testing.T) {
+	input := `# HELP gatewayapi_gateway_info Gateway information
+# TYPE gatewayapi_gateway_info info
+gatewayapi_gateway_info{gatewayclass_name="testgatewayclass1",name="testgateway1",namespace="default"} 1
+# HELP gatewayapi_gateway_labels Kubernetes labels converted to Prometheus labels.
+# TYPE gatewayapi_gateway_labels info
+# HELP gatewayapi_httproute_hostname_info Hostname information
+# TYPE gatewayapi_httproute_hostname_info info
+gatewayapi_httproute_hostname_info{hostname="a.example.com,b=c",name="testroute1"} 1
+# HELP gatewayapi_gateway_status status condition
+# TYPE gatewayapi_gateway_status gauge
+gatewayapi_gateway_status{name="testgateway1",type="Accepted"} 1
+gatewayapi_gateway_status{name="testgateway1",type="Programmed"} 0
+# HELP kube_pod_phase The pod's current phase\nwith an escaped \\ backslash.
+# TYPE kube_pod_phase stateset
+kube_pod_phase{kube_pod_phase="Pending",pod="p"} 0
+kube_pod_phase{kube_pod_phase="Running",pod="p"} 1
+# HELP gatewayapi_gateway_created created timestamp
+# TYPE gatewayapi_gateway_created gauge
+gatewayapi_gateway_created{message="say \"hi\"\nbye \\o/"} 1.692658388e+09
+# TYPE http_requests counter
+http_requests_total 1027 1395066363000
+http_requests_created 1.395066363e+09
+process_open_fds 12
+up NaN
+# EOF
+ignored 1
+`
+
+	families, err := ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	expected := []*MetricFamily{
+		{Name: "gatewayapi_gateway_info", Help: "Gateway information", Type: Info, Samples: []Sample{
+			{Name: "gatewayapi_gateway_info", Labels: map[string]string{"gatewayclass_name": "testgatewayclass1", "name": "testgateway1", "namespace": "default"}, Value: 1},
+		}},
+		{Name: "gatewayapi_gateway_labels", Help: "Kubernetes labels converted to Prometheus labels.", Type: Info},
+		{Name: "gatewayapi_httproute_hostname_info", Help: "Hostname information", Type: Info, Samples: []Sample{
+			{Name: "gatewayapi_httproute_hostname_info", Labels: map[string]string{"hostname": "a.example.com,b=c", "name": "testroute1"}, Value: 1},
+		}},
+		{Name: "gatewayapi_gateway_status", Help: "status condition", Type: Gauge, Samples: []Sample{
+			{Name: "gatewayapi_gateway_status", Labels: map[string]string{"name": "testgateway1", "type": "Accepted"}, Value: 1},
+			{Name: "gatewayapi_gateway_status", Labels: map[string]string{"name": "testgateway1", "type": "Programmed"}, Value: 0},
+		}},
+		{Name: "kube_pod_phase", Help: "The pod's current phase\nwith an escaped \\ backslash.", Type: StateSet, Samples: []Sample{
+			{Name: "kube_pod_phase", Labels: map[string]string{"kube_pod_phase": "Pending", "pod": "p"}, Value: 0},
+			{Name: "kube_pod_phase", Labels: map[string]string{"kube_pod_phase": "Running", "pod": "p"}, Value: 1},
+		}},
+		{Name: "gatewayapi_gateway_created", Help: "created timestamp", Type: Gauge, Samples: []Sample{
+			{Name: "gatewayapi_gateway_created", Labels: map[string]string{"message": "say \"hi\"\nbye \\o/"}, Value: 1.692658388e+09},
+		}},
+		{Name: "http_requests", Type: Counter, Samples: []Sample{
+			{Name: "http_requests_total", Labels: map[string]string{}, Value: 1027, Timestamp: float64Ptr(1395066363000)},
+			{Name: "http_requests_created", Labels: map[string]string{}, Value: 1.395066363e+09},
+		}},
+		{Name: "process_open_fds", Type: Unknown, Samples: []Sample{
+			{Name: "process_open_fds", Labels: map[string]string{}, Value: 12},
+		}},
+		{Name: "up", Type: Unknown, Samples: []Sample{
+			{Name: "up", Labels: map[string]string{}, Value: math.NaN()},
+		}},
+	}
+
+	if diff := cmp.Diff(expected, families, cmp.Comparer(func(a, b float64) bool {
+		return a == b || (math.IsNaN(a) && math.IsNaN(b))
+	})); diff != "" {
+		t.Fatalf("unexpected families (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, input := range []string{
+		"# TYPE foo histogramm\n",
+		"foo{bar=\"baz} 1\n",
+		"foo{bar=baz} 1\n",
+		"foo{bar=\"a\",bar=\"b\"} 1\n",
+		"foo{bar=\"a\"baz=\"b\"} 1\n",
+		"foo{bar=\"a\" baz=\"b\"} 1\n",
+		"foo{bar=\"\\t\"} 1\n",
+		"foo{bar=\"baz\"}\n",
+		"foo one\n",
+		"{bar=\"baz\"} 1\n",
+	} {
+		if _, err := ParseString(input); err == nil {
+			t.Errorf("expected an error parsing %q", input)
+		}
+	}
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	input := strings.Join([]string{
+		`# HELP gatewayapi_gateway_status status condition\nwith a newline`,
+		`# TYPE gatewayapi_gateway_status gauge`,
+		`gatewayapi_gateway_status{name="testgateway1",reason="a \"quoted\" \\ reason",type="Accepted"} 1`,
+		`gatewayapi_gateway_status{name="testgateway1",type="Programmed"} 0`,
+		`# HELP gatewayapi_gateway_created created timestamp`,
+		`# TYPE gatewayapi_gateway_created gauge`,
+		`gatewayapi_gateway_created{name="testgateway1"} 1.692658388e+09`,
+		`up 1`,
+		``,
+	}, "\n")
+
+	families, err := ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	out := &strings.Builder{}
+	if err := Write(out, families...); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	expected := strings.Replace(input, "up 1", "# TYPE up unknown\nup 1", 1)
+	if out.String() != expected {
+		t.Fatalf("unexpected output, got:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
@@ -0,0 +1,169 @@
+// Package cases runs the declarative metric test cases in this directory.
+//
+// Each subdirectory is a scenario with the Kubernetes objects to render
+// metrics for, including their status, and an expected.yaml listing the
+// series that must be exposed for them. See README.md for the format.
+package cases
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/kuadrant/gateway-api-state-metrics/tests/expect"
+	"github.com/kuadrant/gateway-api-state-metrics/tests/harness"
+)
+
+const (
+	customResourceStateConfig = "../../config/kuadrant/custom-resource-state.yaml"
+	expectedFile              = "expected.yaml"
+)
+
+var crdPaths = []string{
+	"../../config/gateway-api/crd/standard",
+	"../../config/kuadrant/crd",
+}
+
+// expected is the content of an expected.yaml file.
+type expected struct {
+	Series []series `yaml:"series"`
+}
+
+// series selects the series named Name that have all of Labels. An empty
+// label value matches series without the label.
+//
+// Value is either a number or "timestamp" for a unix timestamp in the past.
+// Count is the exact number of matching series, 0 to expect none. If neither
+// is set, at least one series must match.
+type series struct {
+	Name   string        `yaml:"name"`
+	Labels expect.Labels `yaml:"labels"`
+	Value  *string       `yaml:"value"`
+	Count  *int          `yaml:"count"`
+}
+
+func TestCases(t *testing.T) {
+	entries, err := os.ReadDir(".")
+	if err != nil {
+		t.Fatalf("failed to list test cases: %v", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := entry.Name()
+		t.Run(dir, func(t *testing.T) {
+			runCase(t, dir)
+		})
+	}
+}
+
+func runCase(t *testing.T, dir string) {
+	exp, manifests, err := readCase(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := harness.New(customResourceStateConfig)
+	if err != nil {
+		t.Fatalf("failed to setup harness: %v", err)
+	}
+	if err := h.LoadCRDs(crdPaths...); err != nil {
+		t.Fatalf("failed to load CRDs: %v", err)
+	}
+	if err := h.LoadManifests(manifests...); err != nil {
+		t.Fatalf("failed to load manifests: %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := h.Metrics(buf); err != nil {
+		t.Fatalf("failed to render metrics: %v", err)
+	}
+	m, err := expect.Parse(buf)
+	if err != nil {
+		t.Fatalf("failed to parse metrics: %v", err)
+	}
+
+	for i, s := range exp.Series {
+		if s.Name == "" {
+			t.Errorf("%s: series %d has no name", expectedFile, i)
+			continue
+		}
+		value, err := parseValue(s.Value)
+		if err != nil {
+			t.Errorf("%s: series %d (%s): %v", expectedFile, i, s.Name, err)
+			continue
+		}
+
+		switch {
+		case s.Count == nil:
+			m.Series(t, s.Name, s.Labels, value)
+		case *s.Count == 0:
+			m.None(t, s.Name, s.Labels)
+		default:
+			m.Count(t, s.Name, s.Labels, *s.Count)
+			if s.Value != nil {
+				m.Series(t, s.Name, s.Labels, value)
+			}
+		}
+	}
+}
+
+// readCase reads the expected series of the case in dir and returns the
+// paths of the manifests next to it.
+func readCase(dir string) (*expected, []string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, expectedFile))
+	if err != nil {
+		return nil, nil, err
+	}
+	exp := &expected{}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(exp); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode %s: %w", filepath.Join(dir, expectedFile), err)
+	}
+	if len(exp.Series) == 0 {
+		return nil, nil, fmt.Errorf("%s has no series", filepath.Join(dir, expectedFile))
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	var manifests []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || name == expectedFile {
+			continue
+		}
+		switch filepath.Ext(name) {
+		case ".yaml", ".yml", ".json":
+			manifests = append(manifests, filepath.Join(dir, name))
+		}
+	}
+	if len(manifests) == 0 {
+		return nil, nil, fmt.Errorf("%s has no manifests", dir)
+	}
+	return exp, manifests, nil
+}
+
+func parseValue(value *string) (expect.Value, error) {
+	if value == nil {
+		return expect.Any(), nil
+	}
+	if strings.TrimSpace(*value) == "timestamp" {
+		return expect.TimestampInPast(), nil
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(*value), 64)
+	if err != nil {
+		return nil, fmt.Errorf("value must be a number or \"timestamp\", got %q", *value)
+	}
+	return expect.Equal(v), nil
+}
@@ -28,7 +28,6 @@ esac
 
 NODE_IMAGE_NAME="docker.io/kindest/node"
 KUBERNETES_VERSION=${KUBERNETES_VERSION:-"v1.27.0"}
-KUBE_STATE_METRICS_LOG_DIR=./log
 E2E_SETUP_KIND=${E2E_SETUP_KIND:-}
 E2E_SETUP_KUBECTL=${E2E_SETUP_KUBECTL:-}
 KIND_VERSION=v0.19.0
@@ -45,6 +44,7 @@ function finish() {
     kill %1 || true
     kubectl delete -f config/examples/kube-state-metrics/ || true
     kubectl delete -f tests/manifests/ || true
+    kubectl delete -f tests/manifests/experimental/ || true
 }
 
 function setup_kind() {
@@ -124,8 +124,6 @@ kubectl version
 docker pull "${KUBE_STATE_METRICS_IMAGE_NAME}:${KUBE_STATE_METRICS_IMAGE_TAG}"
 kind load docker-image "${KUBE_STATE_METRICS_IMAGE_NAME}:${KUBE_STATE_METRICS_IMAGE_TAG}"
 
-mkdir -p ${KUBE_STATE_METRICS_LOG_DIR}
-
 trap finish EXIT
 
 # create Gateway API CRDs
@@ -165,30 +163,26 @@ echo "kube-state-metrics is up and running"
 echo "start e2e test for kube-state-metrics"
 KSM_HTTP_METRICS_URL='http://localhost:8001/api/v1/namespaces/kube-system/services/kube-state-metrics:http-metrics/proxy'
 KSM_TELEMETRY_URL='http://localhost:8001/api/v1/namespaces/kube-system/services/kube-state-metrics:telemetry/proxy'
-go test -mod=readonly -v ./tests/e2e/main_test.go --ksm-http-metrics-url=${KSM_HTTP_METRICS_URL} --ksm-telemetry-url=${KSM_TELEMETRY_URL}
+# also checks kube-state-metrics stays healthy and doesn't log CustomResourceState errors
+go test -mod=readonly -v ./tests/e2e/ --ksm-http-metrics-url=${KSM_HTTP_METRICS_URL} --ksm-telemetry-url=${KSM_TELEMETRY_URL}
 
-# TODO: re-implement the following test cases in Go with the goal of removing this file.
-echo "access kube-state-metrics metrics endpoint"
-curl -s "http://localhost:8001/api/v1/namespaces/kube-system/services/kube-state-metrics:http-metrics/proxy/metrics" >${KUBE_STATE_METRICS_LOG_DIR}/metrics
+echo "switch to the Gateway API experimental channel"
 
-KUBE_STATE_METRICS_STATUS=$(curl -s "http://localhost:8001/api/v1/namespaces/kube-system/services/kube-state-metrics:http-metrics/proxy/healthz")
-if [[ "${KUBE_STATE_METRICS_STATUS}" == "OK" ]]; then
-    echo "kube-state-metrics is still running after accessing metrics endpoint"
-fi
+# replace the standard CRDs, which also deletes the test resources, and the
+# config with the experimental channel ones
+kubectl delete -f ./config/gateway-api/crd/standard/
+kubectl create -f ./config/gateway-api/crd/experimental/
+kubectl create configmap custom-resource-state --from-file=custom-resource-state.yaml=./config/experimental/custom-resource-state.yaml --dry-run=client -o yaml | kubectl -n kube-system apply -f -
+kubectl patch clusterrole kube-state-metrics --type=json -p "$(cat ./config/experimental/clusterrole-patch.yaml)"
+kubectl -n kube-system rollout restart deployment/kube-state-metrics
+kubectl -n kube-system rollout status deployment/kube-state-metrics --timeout=2m
 
-# wait for klog to flush to log file
-sleep 33
-klog_err=E$(date +%m%d)
-echo "check for errors in logs"
-
-output_logs=$(kubectl --namespace=kube-system logs deployment/kube-state-metrics kube-state-metrics)
-if echo "${output_logs}" | grep "^${klog_err}"; then
-    echo ""
-    echo "==========================================="
-    echo "Found errors in the kube-state-metrics logs"
-    echo "==========================================="
-    echo ""
-    echo "${output_logs}"
-    # TODO: Assess errors for fixing
-    #exit 1
-fi
+# Create the experimental test resources and the Gateway they refer to
+kubectl create -f ./tests/manifests/testgateway1.yaml -f ./tests/manifests/experimental/
+kubectl replace --subresource=status -f ./tests/manifests/testgateway1.yaml -f ./tests/manifests/experimental/
+
+set +e
+kube_state_metrics_up
+set -e
+
+go test -mod=readonly -v ./tests/e2e/ -run 'TestExperimentalMetricsAvailable|TestHealthzAfterScrape|TestNoCustomResourceStateErrors|TestScanLogs' --experimental --ksm-http-metrics-url=${KSM_HTTP_METRICS_URL} --ksm-telemetry-url=${KSM_TELEMETRY_URL}
@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+// maxConfigSize is the size the CustomResourceState configs must stay below,
+// the maximum size of the last-applied-configuration annotation kubectl apply
+// stores the ConfigMap in.
+const maxConfigSize = 262144
+
+// TestConfigSize checks the CustomResourceState configs fit in the ConfigMap
+// they are deployed with. The annotation has the config as a JSON string, so
+// its escaped size is checked.
+func TestConfigSize(t *testing.T) {
+	for _, config := range []string{
+		"../../config/default/custom-resource-state.yaml",
+		"../../config/experimental/custom-resource-state.yaml",
+		"../../config/kuadrant/custom-resource-state.yaml",
+	} {
+		data, err := os.ReadFile(config)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", config, err)
+		}
+		encoded, err := json.Marshal(string(data))
+		if err != nil {
+			t.Fatalf("failed to encode %s: %v", config, err)
+		}
+		if len(encoded) >= maxConfigSize {
+			t.Errorf("%s is %d bytes as JSON, it must be below %d bytes to be applied with kubectl apply", config, len(encoded), maxConfigSize)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package metrics
+
+import (
+	"bufio"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/kuadrant/gateway-api-state-metrics/pkg/crs"
+)
+
+// customResourceStateConfigs are the configs whose metric families must all
+// be covered by a test.
+var customResourceStateConfigs = []string{
+	"../../config/default/custom-resource-state.yaml",
+	"../../config/kuadrant/custom-resource-state-kuadrant.yaml",
+	"../../config/experimental/custom-resource-state.yaml",
+}
+
+// TestMetricCoverage checks every metric family declared in the
+// CustomResourceState configs is covered by a test. A family is covered if
+// it has series in a golden file, is expected by a test case in tests/cases,
+// or is asserted on with Series or Count in the tests of this package.
+func TestMetricCoverage(t *testing.T) {
+	declared := map[string]bool{}
+	for _, config := range customResourceStateConfigs {
+		families, err := declaredFamilies(config)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", config, err)
+		}
+		for _, family := range families {
+			declared[family] = true
+		}
+	}
+
+	covered := map[string]bool{}
+	for _, find := range []func() ([]string, error){goldenFamilies, caseFamilies, assertedFamilies} {
+		families, err := find()
+		if err != nil {
+			t.Fatalf("failed to find covered metric families: %v", err)
+		}
+		for _, family := range families {
+			covered[family] = true
+		}
+	}
+
+	var uncovered []string
+	for family := range declared {
+		if !covered[family] {
+			uncovered = append(uncovered, family)
+		}
+	}
+	sort.Strings(uncovered)
+	if len(uncovered) > 0 {
+		t.Errorf("%d metric families are not covered by an assertion, golden file or test case:\n%s", len(uncovered), strings.Join(uncovered, "\n"))
+	}
+}
+
+// declaredFamilies returns the names of the metric families declared in a
+// CustomResourceState config.
+func declaredFamilies(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	resources, err := crs.Read(f)
+	if err != nil {
+		return nil, err
+	}
+
+	var families []string
+	for _, resource := range resources {
+		for _, generator := range resource.Metrics {
+			families = append(families, crs.FamilyName(resource, generator))
+		}
+	}
+	return families, nil
+}
+
+// goldenFamilies returns the metric families with at least one series in the
+// golden files. Families without series only have their HELP and TYPE lines
+// in the golden files, which doesn't cover them.
+func goldenFamilies() ([]string, error) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.golden"))
+	if err != nil {
+		return nil, err
+	}
+
+	var families []string
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			families = append(families, line[:strings.IndexAny(line, "{ ")])
+		}
+		f.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return families, nil
+}
+
+// caseFamilies returns the metric families the test cases in tests/cases
+// expect series for.
+func caseFamilies() ([]string, error) {
+	files, err := filepath.Glob(filepath.Join("..", "cases", "*", "expected.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	var families []string
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		expected := struct {
+			Series []struct {
+				Name  string `yaml:"name"`
+				Count *int   `yaml:"count"`
+			} `yaml:"series"`
+		}{}
+		if err := yaml.Unmarshal(data, &expected); err != nil {
+			return nil, err
+		}
+		for _, s := range expected.Series {
+			if s.Count == nil || *s.Count > 0 {
+				families = append(families, s.Name)
+			}
+		}
+	}
+	return families, nil
+}
+
+// assertedFamilies returns the metric families passed by name to the Series
+// and Count assertions in the tests of this package. Counts of 0 only assert
+// that a family has no series, so they don't cover it.
+func assertedFamilies() ([]string, error) {
+	files, err := filepath.Glob("*_test.go")
+	if err != nil {
+		return nil, err
+	}
+
+	var families []string
+	fset := token.NewFileSet()
+	for _, file := range files {
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) < 2 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || (sel.Sel.Name != "Series" && sel.Sel.Name != "Count") {
+				return true
+			}
+			if sel.Sel.Name == "Count" && len(call.Args) == 4 {
+				if n, ok := call.Args[3].(*ast.BasicLit); ok && n.Value == "0" {
+					return true
+				}
+			}
+			lit, ok := call.Args[1].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return true
+			}
+			if name, err := strconv.Unquote(lit.Value); err == nil {
+				families = append(families, name)
+			}
+			return true
+		})
+	}
+	return families, nil
+}
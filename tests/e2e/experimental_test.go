@@ -0,0 +1,264 @@
+package metrics
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/kuadrant/gateway-api-state-metrics/tests/expect"
+	"github.com/kuadrant/gateway-api-state-metrics/tests/harness"
+)
+
+// TestExperimentalMetricsAvailable checks the metrics of the experimental
+// channel config for the test manifests and the experimental ones. Without a
+// kube-state-metrics url they are rendered offline with the experimental
+// channel CRDs, together with the objects being deleted in testdata, which
+// can't be created in a cluster.
+func TestExperimentalMetricsAvailable(t *testing.T) {
+	if framework != nil && !experimental {
+		t.Skip("the experimental channel CRDs are not installed in the e2e cluster, see --experimental")
+	}
+
+	m := parseMetrics(t)
+	if framework == nil {
+		m = renderExperimentalMetrics(t)
+	}
+
+	testVersions(t, m)
+	testExperimentalGateway(t, m)
+	testReferenceGrant(t, m)
+	testExperimentalBackendTLSPolicy(t, m)
+	testBackendLBPolicy(t, m)
+	testXBackendTrafficPolicy(t, m)
+	testXListenerSet(t, m)
+	if framework == nil {
+		testExperimentalDeleting(t, m)
+	}
+}
+
+// renderExperimentalMetrics renders the experimental channel config offline
+// for the test manifests, the experimental ones and the objects being deleted
+// in testdata, with the experimental channel CRDs.
+func renderExperimentalMetrics(t *testing.T) *expect.Metrics {
+	h, err := harness.New("../../config/experimental/custom-resource-state.yaml")
+	if err != nil {
+		t.Fatalf("failed to setup harness: %v", err)
+	}
+	if err := h.LoadCRDs("../../config/gateway-api/crd/experimental"); err != nil {
+		t.Fatalf("failed to load CRDs: %v", err)
+	}
+	if err := h.LoadManifests("../manifests", "../manifests/experimental", "testdata/experimental-deleting.yaml"); err != nil {
+		t.Fatalf("failed to load manifests: %v", err)
+	}
+	buf := &bytes.Buffer{}
+	if err := h.Metrics(buf); err != nil {
+		t.Fatalf("failed to render metrics: %v", err)
+	}
+	m, err := expect.Parse(buf)
+	if err != nil {
+		t.Fatalf("failed to parse metrics: %v", err)
+	}
+	return m
+}
+
+// testExperimentalDeleting checks the _labels and _deleted metrics of the
+// objects in testdata/experimental-deleting.yaml.
+func testExperimentalDeleting(t *testing.T, m *expect.Metrics) {
+	deleting := func(kind string) expect.Labels {
+		return expect.Labels{"name": "deleting-" + kind}
+	}
+	echo := expect.Labels{"app": "echo"}
+	deletionTimestamp := expect.Equal(1747044000)
+
+	m.Series(t, "gatewayapi_referencegrant_labels", deleting("referencegrant").With(echo), expect.Equal(1))
+	m.Series(t, "gatewayapi_referencegrant_deleted", deleting("referencegrant"), deletionTimestamp)
+	m.Series(t, "gatewayapi_backendlbpolicy_labels", deleting("backendlbpolicy").With(echo), expect.Equal(1))
+	m.Series(t, "gatewayapi_backendlbpolicy_deleted", deleting("backendlbpolicy"), deletionTimestamp)
+	m.Series(t, "gatewayapi_xbackendtrafficpolicy_labels", deleting("xbackendtrafficpolicy").With(echo), expect.Equal(1))
+	m.Series(t, "gatewayapi_xbackendtrafficpolicy_deleted", deleting("xbackendtrafficpolicy"), deletionTimestamp)
+	m.Series(t, "gatewayapi_xlistenerset_labels", deleting("xlistenerset").With(echo), expect.Equal(1))
+	m.Series(t, "gatewayapi_xlistenerset_deleted", deleting("xlistenerset"), deletionTimestamp)
+}
+
+func testExperimentalGateway(t *testing.T, m *expect.Metrics) {
+	gateway1 := object(gatewayAPIGroup, "Gateway", "default", "testgateway1")
+	gateway2 := object(gatewayAPIGroup, "Gateway", "default", "testgateway2")
+
+	m.Series(t, "gatewayapi_gateway_info", gateway1.With(expect.Labels{"customresource_version": "v1"}), expect.Equal(1))
+	m.Series(t, "gatewayapi_gateway_status", gateway2.With(expect.Labels{"type": "Programmed"}), expect.Equal(1))
+
+	m.Count(t, "gatewayapi_gateway_infrastructure_info", gateway1, 0)
+	m.Series(t, "gatewayapi_gateway_infrastructure_info", gateway2.With(expect.Labels{
+		"parameters_ref_group": "example.com",
+		"parameters_ref_kind":  "GatewayParameters",
+		"parameters_ref_name":  "testgateway2-params",
+	}), expect.Equal(1))
+
+	m.Count(t, "gatewayapi_gateway_backend_tls_client_certificate_ref_info", gateway1, 0)
+	m.Series(t, "gatewayapi_gateway_backend_tls_client_certificate_ref_info", gateway2.With(expect.Labels{
+		"certificate_group":     "",
+		"certificate_kind":      "Secret",
+		"certificate_name":      "gateway-client-cert",
+		"certificate_namespace": "default",
+	}), expect.Equal(1))
+}
+
+func testReferenceGrant(t *testing.T, m *expect.Metrics) {
+	referencegrant1 := object(gatewayAPIGroup, "ReferenceGrant", "default", "testreferencegrant1")
+
+	m.Series(t, "gatewayapi_referencegrant_created", referencegrant1, expect.TimestampInPast())
+	m.Series(t, "gatewayapi_referencegrant_metadata_generation", referencegrant1, expect.Equal(1))
+
+	m.Count(t, "gatewayapi_referencegrant_from_info", referencegrant1, 2)
+	m.Series(t, "gatewayapi_referencegrant_from_info", referencegrant1.With(expect.Labels{
+		"from_group":     gatewayAPIGroup,
+		"from_kind":      "HTTPRoute",
+		"from_namespace": "apps",
+	}), expect.Equal(1))
+
+	m.Count(t, "gatewayapi_referencegrant_to_info", referencegrant1, 2)
+	m.Series(t, "gatewayapi_referencegrant_to_info", referencegrant1.With(expect.Labels{
+		"to_group": "",
+		"to_kind":  "Service",
+		"to_name":  "backend",
+	}), expect.Equal(1))
+	m.Series(t, "gatewayapi_referencegrant_to_info", referencegrant1.With(expect.Labels{
+		"to_kind": "Secret",
+		"to_name": "",
+	}), expect.Equal(1))
+}
+
+// testExperimentalBackendTLSPolicy checks the target_info metric of a
+// BackendTLSPolicy that targets several Services with spec.targetRefs.
+func testExperimentalBackendTLSPolicy(t *testing.T, m *expect.Metrics) {
+	policy2 := object(gatewayAPIGroup, "BackendTLSPolicy", "default", "testbackendtlspolicy2")
+
+	m.Count(t, "gatewayapi_backendtlspolicy_target_info", policy2, 2)
+	m.Series(t, "gatewayapi_backendtlspolicy_target_info", policy2.With(expect.Labels{
+		"target_group":        "",
+		"target_kind":         "Service",
+		"target_name":         "backend",
+		"target_section_name": "https",
+	}), expect.Equal(1))
+	m.Series(t, "gatewayapi_backendtlspolicy_target_info", policy2.With(expect.Labels{
+		"target_kind":         "Service",
+		"target_name":         "backend-canary",
+		"target_section_name": "",
+	}), expect.Equal(1))
+	m.Count(t, "gatewayapi_backendtlspolicy_status_ancestor_condition", policy2, 2)
+	m.Series(t, "gatewayapi_backendtlspolicy_status_ancestor_condition", policy2.With(expect.Labels{
+		"ancestor_name":         "testgateway1",
+		"ancestor_section_name": "",
+		"controller_name":       "example.com/gateway-controller",
+		"type":                  "Accepted",
+	}), expect.Equal(1))
+	m.Series(t, "gatewayapi_backendtlspolicy_status_ancestor_condition", policy2.With(expect.Labels{
+		"ancestor_group":        gatewayAPIGroup,
+		"ancestor_kind":         "Gateway",
+		"ancestor_name":         "testgateway2",
+		"ancestor_namespace":    "default",
+		"ancestor_section_name": "https",
+		"controller_name":       "example.com/other-controller",
+		"type":                  "Accepted",
+		"reason":                "NoValidCACertificate",
+	}), expect.Equal(0))
+}
+
+func testBackendLBPolicy(t *testing.T, m *expect.Metrics) {
+	policy1 := object(gatewayAPIGroup, "BackendLBPolicy", "default", "testbackendlbpolicy1")
+
+	m.Series(t, "gatewayapi_backendlbpolicy_created", policy1, expect.TimestampInPast())
+	m.Series(t, "gatewayapi_backendlbpolicy_metadata_generation", policy1, expect.Equal(1))
+	m.Series(t, "gatewayapi_backendlbpolicy_target_info", policy1.With(expect.Labels{
+		"target_group": "",
+		"target_kind":  "Service",
+		"target_name":  "legacy-backend",
+	}), expect.Equal(1))
+	m.Series(t, "gatewayapi_backendlbpolicy_session_persistence_info", policy1.With(expect.Labels{
+		"type":                 "Header",
+		"session_name":         "legacy-session",
+		"absolute_timeout":     "30m",
+		"idle_timeout":         "",
+		"cookie_lifetime_type": "",
+	}), expect.Equal(1))
+	m.Series(t, "gatewayapi_backendlbpolicy_status_ancestor_condition", policy1.With(expect.Labels{
+		"ancestor_kind":   "Gateway",
+		"ancestor_name":   "testgateway2",
+		"controller_name": "example.com/gateway-controller",
+		"type":            "Accepted",
+		"reason":          "Accepted",
+	}), expect.Equal(1))
+	m.Series(t, "gatewayapi_backendlbpolicy_status_condition_observed_generation", policy1.With(expect.Labels{
+		"ancestor_name": "testgateway2",
+		"type":          "Accepted",
+	}), expect.Equal(1))
+}
+
+func testXBackendTrafficPolicy(t *testing.T, m *expect.Metrics) {
+	policy1 := object(gatewayAPIExperimentalGroup, "XBackendTrafficPolicy", "default", "testxbackendtrafficpolicy1")
+
+	m.Series(t, "gatewayapi_xbackendtrafficpolicy_created", policy1, expect.TimestampInPast())
+	m.Series(t, "gatewayapi_xbackendtrafficpolicy_metadata_generation", policy1, expect.Equal(1))
+	m.Series(t, "gatewayapi_xbackendtrafficpolicy_target_info", policy1.With(expect.Labels{
+		"target_group": "",
+		"target_kind":  "Service",
+		"target_name":  "backend",
+	}), expect.Equal(1))
+	m.Series(t, "gatewayapi_xbackendtrafficpolicy_session_persistence_info", policy1.With(expect.Labels{
+		"type":                 "Cookie",
+		"session_name":         "backend-session",
+		"absolute_timeout":     "1h",
+		"idle_timeout":         "10m",
+		"cookie_lifetime_type": "Permanent",
+	}), expect.Equal(1))
+	m.Series(t, "gatewayapi_xbackendtrafficpolicy_status_ancestor_condition", policy1.With(expect.Labels{
+		"ancestor_kind":   "Gateway",
+		"ancestor_name":   "testgateway2",
+		"controller_name": "example.com/gateway-controller",
+		"type":            "Accepted",
+		"reason":          "Accepted",
+	}), expect.Equal(1))
+	m.Series(t, "gatewayapi_xbackendtrafficpolicy_status_condition_observed_generation", policy1.With(expect.Labels{
+		"ancestor_name": "testgateway2",
+		"type":          "Accepted",
+	}), expect.Equal(1))
+}
+
+func testXListenerSet(t *testing.T, m *expect.Metrics) {
+	listenerset1 := object(gatewayAPIExperimentalGroup, "XListenerSet", "default", "testxlistenerset1")
+
+	m.Series(t, "gatewayapi_xlistenerset_created", listenerset1, expect.TimestampInPast())
+	m.Series(t, "gatewayapi_xlistenerset_metadata_generation", listenerset1, expect.Equal(1))
+	m.Series(t, "gatewayapi_xlistenerset_parent_info", listenerset1.With(expect.Labels{
+		"parent_group":     gatewayAPIGroup,
+		"parent_kind":      "Gateway",
+		"parent_name":      "testgateway2",
+		"parent_namespace": "",
+	}), expect.Equal(1))
+
+	m.Count(t, "gatewayapi_xlistenerset_listener_info", listenerset1, 2)
+	m.Series(t, "gatewayapi_xlistenerset_listener_info", listenerset1.With(expect.Labels{
+		"listener_name": "team-a",
+		"hostname":      "team-a.example.com",
+		"port":          "443",
+		"protocol":      "HTTPS",
+		"tls_mode":      "Terminate",
+	}), expect.Equal(1))
+
+	m.Series(t, "gatewayapi_xlistenerset_status", listenerset1.With(expect.Labels{"type": "Accepted"}), expect.Equal(1))
+	m.Series(t, "gatewayapi_xlistenerset_status", listenerset1.With(expect.Labels{"type": "Programmed", "reason": "ListenersNotValid"}), expect.Equal(0))
+	m.Series(t, "gatewayapi_xlistenerset_status_last_transition_time", listenerset1.With(expect.Labels{"type": "Programmed"}), expect.Equal(1747041267))
+	m.Series(t, "gatewayapi_xlistenerset_status_condition_observed_generation", listenerset1.With(expect.Labels{"type": "Programmed"}), expect.Equal(1))
+	m.Series(t, "gatewayapi_xlistenerset_status_listener_attached_routes", listenerset1.With(expect.Labels{"listener_name": "team-a"}), expect.Equal(1))
+
+	m.Count(t, "gatewayapi_xlistenerset_status_listener_condition", listenerset1, 2)
+	m.Series(t, "gatewayapi_xlistenerset_status_listener_condition", listenerset1.With(expect.Labels{
+		"listener_name": "team-b",
+		"type":          "ResolvedRefs",
+		"reason":        "InvalidCertificateRef",
+	}), expect.Equal(0))
+	m.Series(t, "gatewayapi_xlistenerset_status_listener_supported_kinds", listenerset1.With(expect.Labels{
+		"listener_name": "team-b",
+		"group":         gatewayAPIGroup,
+		"kind":          "HTTPRoute",
+	}), expect.Equal(1))
+}
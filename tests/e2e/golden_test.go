@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/kuadrant/gateway-api-state-metrics/pkg/openmetrics"
+	"github.com/kuadrant/gateway-api-state-metrics/tests/expect"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata with the current metrics")
+
+// timestampFamilySuffixes are the metric families whose values are set by the
+// API server rather than the test manifests. Their values are checked with
+// expect.TimestampInPast and replaced with a placeholder in golden files.
+var timestampFamilySuffixes = []string{"_created", "_deleted"}
+
+const timestampPlaceholder = "<timestamp>"
+
+func TestGoldenMetrics(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	err := metrics(buf)
+	if err != nil {
+		t.Fatalf("failed to get metrics from kube-state-metrics: %v", err)
+	}
+	exposition := buf.String()
+
+	for _, prefix := range []string{"gatewayapi", "kuadrant"} {
+		prefix := prefix
+		t.Run(prefix, func(t *testing.T) {
+			golden := filepath.Join("testdata", prefix+".golden")
+			actual := snapshot(t, exposition, prefix+"_")
+
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+					t.Fatalf("failed to create testdata dir: %v", err)
+				}
+				if err := os.WriteFile(golden, []byte(actual), 0o644); err != nil {
+					t.Fatalf("failed to update golden file %s: %v", golden, err)
+				}
+				return
+			}
+
+			expected, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("failed to read golden file %s (run with -update to create it): %v", golden, err)
+			}
+			if diff := cmp.Diff(strings.Split(string(expected), "\n"), strings.Split(actual, "\n")); diff != "" {
+				t.Errorf("metrics do not match %s (-expected +actual), run with -update if the change is intended:\n%s", golden, diff)
+			}
+		})
+	}
+}
+
+// snapshot returns the metric families with the given prefix in a form that
+// is stable across runs: series are sorted within their family and timestamp
+// values are replaced with a placeholder.
+func snapshot(t *testing.T, exposition string, prefix string) string {
+	families, err := openmetrics.ParseString(exposition)
+	if err != nil {
+		t.Fatalf("failed to parse metrics: %v", err)
+	}
+
+	out := &strings.Builder{}
+	for _, family := range families {
+		if !strings.HasPrefix(family.Name, prefix) {
+			continue
+		}
+		header := *family
+		header.Samples = nil
+		if err := openmetrics.Write(out, &header); err != nil {
+			t.Fatalf("failed to write metrics: %v", err)
+		}
+
+		var series []string
+		for _, sample := range family.Samples {
+			series = append(series, normalizeTimestamp(t, family.Name, sample))
+		}
+		sort.Strings(series)
+		for _, line := range series {
+			out.WriteString(line)
+			out.WriteByte('\n')
+		}
+	}
+	return out.String()
+}
+
+func normalizeTimestamp(t *testing.T, family string, sample openmetrics.Sample) string {
+	for _, suffix := range timestampFamilySuffixes {
+		if strings.HasSuffix(family, suffix) {
+			if value := expect.TimestampInPast(); !value.Match(sample.Value) {
+				t.Errorf("expected %s to be %s, got %s", sample.Series(), value, openmetrics.FormatValue(sample.Value))
+			}
+			return sample.Series() + " " + timestampPlaceholder
+		}
+	}
+	return sample.String()
+}
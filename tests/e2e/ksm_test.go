@@ -0,0 +1,164 @@
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strings"
+	"testing"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kuadrant/gateway-api-state-metrics/pkg/openmetrics"
+	"github.com/kuadrant/gateway-api-state-metrics/tests/harness"
+)
+
+var (
+	// klogError matches error lines in the klog format, e.g.
+	// E0821 22:53:08.123456       1 registry_factory.go:662] "gatewayapi_gateway_deleted" err="..."
+	klogError = regexp.MustCompile(`^E\d{4} \S+\s+\d+ (\S+)\] (.*)$`)
+
+	// customResourceStateSource is where the CustomResourceState registry logs
+	// values it fails to resolve for a metric.
+	customResourceStateSource = regexp.MustCompile(`^registry_factory\.go:\d+$`)
+
+	// allowedLogErrors are CustomResourceState errors that are expected for
+	// our config and the test objects.
+	allowedLogErrors = []*regexp.Regexp{
+		// _deleted metrics are only set for objects that are being deleted
+		regexp.MustCompile(`^"\w+_deleted" err="\[metadata,deletionTimestamp\]: got nil while resolving path"$`),
+	}
+)
+
+// TestHealthzAfterScrape scrapes the metrics of all test objects again and
+// checks kube-state-metrics is still healthy afterwards.
+func TestHealthzAfterScrape(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := metrics(buf); err != nil {
+		t.Fatalf("failed to get metrics from kube-state-metrics: %v", err)
+	}
+	if _, err := openmetrics.Parse(buf); err != nil {
+		t.Fatalf("failed to parse metrics from kube-state-metrics: %v", err)
+	}
+
+	if framework == nil {
+		t.Skip("no kube-state-metrics url set, the metrics were rendered offline")
+	}
+	healthy, err := framework.KsmClient.IsHealthz()
+	if err != nil {
+		t.Fatalf("failed to check kube-state-metrics health: %v", err)
+	}
+	if !healthy {
+		t.Fatal("kube-state-metrics is not healthy after accessing the metrics endpoint")
+	}
+}
+
+func TestNoCustomResourceStateErrors(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := logs(buf); err != nil {
+		t.Fatalf("failed to get kube-state-metrics logs: %v", err)
+	}
+
+	crsErrors, otherErrors, err := scanLogs(buf)
+	if err != nil {
+		t.Fatalf("failed to read kube-state-metrics logs: %v", err)
+	}
+	for _, line := range otherErrors {
+		t.Logf("kube-state-metrics logged an error: %s", line)
+	}
+	if len(crsErrors) > 0 {
+		t.Errorf("kube-state-metrics failed to resolve the CustomResourceState config for the test objects:\n%s", strings.Join(crsErrors, "\n"))
+	}
+}
+
+func TestScanLogs(t *testing.T) {
+	input := strings.Join([]string{
+		`I0821 22:53:08.100000       1 custom_resource_metrics.go:79] "Custom resource state added metrics" familyNames=[gatewayapi_gateway_info]`,
+		`E0821 22:53:08.123456       1 registry_factory.go:662] "gatewayapi_gateway_deleted" err="[metadata,deletionTimestamp]: got nil while resolving path"`,
+		`E0821 22:53:08.123457       1 registry_factory.go:662] "gatewayapi_gateway_listener_info" err="[spec,listeners]: got nil while resolving path"`,
+		`E0821 22:53:09.000000       1 reflector.go:140] failed to list *unstructured.Unstructured: forbidden`,
+		``,
+	}, "\n")
+
+	crsErrors, otherErrors, err := scanLogs(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("failed to scan logs: %v", err)
+	}
+	if len(crsErrors) != 1 || !strings.Contains(crsErrors[0], "gatewayapi_gateway_listener_info") {
+		t.Errorf("expected only the listener_info error to fail, got %q", crsErrors)
+	}
+	if len(otherErrors) != 1 || !strings.Contains(otherErrors[0], "reflector.go") {
+		t.Errorf("expected the reflector error to be reported, got %q", otherErrors)
+	}
+}
+
+// scanLogs returns the CustomResourceState errors in klog output that are
+// not allowed, and any other errors.
+func scanLogs(r io.Reader) (crsErrors []string, otherErrors []string, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		match := klogError.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		if !customResourceStateSource.MatchString(match[1]) {
+			otherErrors = append(otherErrors, line)
+			continue
+		}
+		if !allowedLogError(match[2]) {
+			crsErrors = append(crsErrors, line)
+		}
+	}
+	return crsErrors, otherErrors, scanner.Err()
+}
+
+func allowedLogError(msg string) bool {
+	for _, allowed := range allowedLogErrors {
+		if allowed.MatchString(msg) {
+			return true
+		}
+	}
+	return false
+}
+
+// kubectlLogs reads the logs of the kube-state-metrics deployment, the same
+// way tests/e2e.sh did. kube-state-metrics logs to stderr, so errors logged
+// while generating metrics are available without waiting for a flush.
+func kubectlLogs(namespace, deployment string) func(io.Writer) error {
+	return func(w io.Writer) error {
+		stderr := &bytes.Buffer{}
+		cmd := exec.Command("kubectl", "--namespace", namespace, "logs", "deployment/"+deployment, "--all-containers")
+		cmd.Stdout = w
+		cmd.Stderr = stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("kubectl logs: %w: %s", err, stderr.String())
+		}
+		return nil
+	}
+}
+
+// renderLogs captures what kube-state-metrics logs while the harness renders
+// the metrics of the test objects.
+func renderLogs(h *harness.Harness) func(io.Writer) error {
+	// klog writes errors to the outputs of all lower severities as well,
+	// which would repeat every error when they share a writer
+	fs := flag.NewFlagSet("klog", flag.PanicOnError)
+	klog.InitFlags(fs)
+	_ = fs.Set("one_output", "true")
+
+	return func(w io.Writer) error {
+		klog.LogToStderr(false)
+		klog.SetOutput(w)
+		defer klog.LogToStderr(true)
+
+		err := h.Metrics(io.Discard)
+		klog.Flush()
+		return err
+	}
+}
@@ -0,0 +1,216 @@
+// Package expect provides order independent assertions on the series exposed
+// by kube-state-metrics.
+//
+// Series are selected by metric name and a subset of their labels, so
+// assertions don't depend on the order series are written in or on the
+// other objects in the cluster:
+//
+//	m.Series(t, "gatewayapi_gateway_status", expect.Labels{"name": "gw", "type": "Programmed"}, expect.Equal(1))
+//	m.Count(t, "gatewayapi_gatewayclass_status_supported_features", expect.Labels{"name": "gc"}, 4)
+//	m.None(t, "gatewayapi_gateway_status", expect.Labels{"name": "gw", "type": "Conflicted"})
+package expect
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kuadrant/gateway-api-state-metrics/pkg/openmetrics"
+)
+
+// Labels selects series that have all of the given labels. An empty value
+// matches a series without the label, as kube-state-metrics omits labels
+// whose path resolves to nothing.
+type Labels map[string]string
+
+// With returns a copy of the labels with other added, overwriting any labels
+// with the same name.
+func (l Labels) With(other Labels) Labels {
+	merged := Labels{}
+	for k, v := range l {
+		merged[k] = v
+	}
+	for k, v := range other {
+		merged[k] = v
+	}
+	return merged
+}
+
+// String returns the labels sorted by name in the exposition format.
+func (l Labels) String() string {
+	return openmetrics.Sample{Labels: l}.Series()
+}
+
+// matches reports whether the sample has all labels, and otherwise describes
+// the labels that differ.
+func (l Labels) matches(s openmetrics.Sample) (bool, string) {
+	var mismatches []string
+	for _, name := range l.names() {
+		if actual := s.Labels[name]; actual != l[name] {
+			mismatches = append(mismatches, fmt.Sprintf("%s=%q, expected %q", name, actual, l[name]))
+		}
+	}
+	return len(mismatches) == 0, strings.Join(mismatches, ", ")
+}
+
+func (l Labels) names() []string {
+	names := make([]string, 0, len(l))
+	for name := range l {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Value matches the value of a series.
+type Value interface {
+	Match(v float64) bool
+	String() string
+}
+
+type equal float64
+
+func (e equal) Match(v float64) bool { return v == float64(e) }
+func (e equal) String() string       { return openmetrics.FormatValue(float64(e)) }
+
+// Equal matches a series with exactly the value v.
+func Equal(v float64) Value {
+	return equal(v)
+}
+
+type timestampInPast struct{}
+
+func (timestampInPast) Match(v float64) bool { return v >= 1 && v <= float64(time.Now().Unix()) }
+func (timestampInPast) String() string       { return "a valid timestamp in the past" }
+
+// TimestampInPast matches a series whose value is a unix timestamp that is
+// not in the future, such as the created timestamp set by the API server.
+func TimestampInPast() Value {
+	return timestampInPast{}
+}
+
+type anyValue struct{}
+
+func (anyValue) Match(float64) bool { return true }
+func (anyValue) String() string     { return "any value" }
+
+// Any matches a series with any value.
+func Any() Value {
+	return anyValue{}
+}
+
+// Metrics holds the parsed series to make assertions on.
+type Metrics struct {
+	samples map[string][]openmetrics.Sample
+}
+
+// New returns Metrics for the given families.
+func New(families []*openmetrics.MetricFamily) *Metrics {
+	m := &Metrics{samples: map[string][]openmetrics.Sample{}}
+	for _, family := range families {
+		for _, s := range family.Samples {
+			m.samples[s.Name] = append(m.samples[s.Name], s)
+		}
+	}
+	return m
+}
+
+// Parse parses metrics in the text exposition format.
+func Parse(r io.Reader) (*Metrics, error) {
+	families, err := openmetrics.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	return New(families), nil
+}
+
+// Select returns the series named name that have all of the given labels.
+func (m *Metrics) Select(name string, labels Labels) []openmetrics.Sample {
+	var selected []openmetrics.Sample
+	for _, s := range m.samples[name] {
+		if ok, _ := labels.matches(s); ok {
+			selected = append(selected, s)
+		}
+	}
+	return selected
+}
+
+// SelectAll returns the series of any name that have all of the given
+// labels, sorted by name.
+func (m *Metrics) SelectAll(labels Labels) []openmetrics.Sample {
+	names := make([]string, 0, len(m.samples))
+	for name := range m.samples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var selected []openmetrics.Sample
+	for _, name := range names {
+		selected = append(selected, m.Select(name, labels)...)
+	}
+	return selected
+}
+
+// Series expects at least one series named name with all of the given labels
+// and a matching value.
+func (m *Metrics) Series(t testing.TB, name string, labels Labels, value Value) {
+	t.Helper()
+	for _, s := range m.Select(name, labels) {
+		if value.Match(s.Value) {
+			return
+		}
+	}
+
+	msg := &strings.Builder{}
+	fmt.Fprintf(msg, "expected a series %s%s with %s, but none matched", name, labels, value)
+	candidates := m.samples[name]
+	if len(candidates) == 0 {
+		fmt.Fprintf(msg, "\nthere are no %s series", name)
+	} else {
+		fmt.Fprintf(msg, "\n%s series:", name)
+	}
+	for _, s := range candidates {
+		ok, mismatches := labels.matches(s)
+		if ok {
+			mismatches = fmt.Sprintf("value %s, expected %s", openmetrics.FormatValue(s.Value), value)
+		}
+		fmt.Fprintf(msg, "\n  %s\n    %s", s, mismatches)
+	}
+	t.Error(msg.String())
+}
+
+// Count expects exactly n series named name with all of the given labels.
+func (m *Metrics) Count(t testing.TB, name string, labels Labels, n int) {
+	t.Helper()
+	selected := m.Select(name, labels)
+	if len(selected) == n {
+		return
+	}
+	t.Errorf("expected %d series matching %s%s, found %d%s", n, name, labels, len(selected), list(selected))
+}
+
+// None expects no series named name with all of the given labels.
+func (m *Metrics) None(t testing.TB, name string, labels Labels) {
+	t.Helper()
+	selected := m.Select(name, labels)
+	if len(selected) == 0 {
+		return
+	}
+	t.Errorf("expected no series matching %s%s, found %d%s", name, labels, len(selected), list(selected))
+}
+
+func list(samples []openmetrics.Sample) string {
+	if len(samples) == 0 {
+		return ""
+	}
+	b := &strings.Builder{}
+	b.WriteByte(':')
+	for _, s := range samples {
+		b.WriteString("\n  ")
+		b.WriteString(s.String())
+	}
+	return b.String()
+}
@@ -0,0 +1,138 @@
+package expect
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const exposition = `# HELP gatewayapi_gateway_status status condition
+# TYPE gatewayapi_gateway_status gauge
+gatewayapi_gateway_status{name="testgateway2",namespace="default",type="Programmed"} 0
+gatewayapi_gateway_status{name="testgateway1",namespace="default",type="Programmed"} 1
+gatewayapi_gateway_status{name="testgateway1",namespace="default",type="Accepted"} 1
+# HELP gatewayapi_gateway_created created timestamp
+# TYPE gatewayapi_gateway_created gauge
+gatewayapi_gateway_created{name="testgateway1",namespace="default"} 1.692658388e+09
+# HELP gatewayapi_gatewayclass_info GatewayClass information
+# TYPE gatewayapi_gatewayclass_info info
+gatewayapi_gatewayclass_info{name="testgatewayclass1"} 1
+`
+
+// recorder records the errors of failed assertions instead of failing the
+// test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Error(args ...any) {
+	r.errors = append(r.errors, fmt.Sprint(args...))
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func parse(t *testing.T) *Metrics {
+	m, err := Parse(strings.NewReader(exposition))
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	return m
+}
+
+func TestPass(t *testing.T) {
+	m := parse(t)
+	gateway1 := Labels{"name": "testgateway1", "namespace": "default"}
+
+	r := &recorder{TB: t}
+	m.Series(r, "gatewayapi_gateway_status", gateway1.With(Labels{"type": "Accepted"}), Equal(1))
+	m.Series(r, "gatewayapi_gateway_status", gateway1.With(Labels{"type": "Programmed"}), Equal(1))
+	m.Series(r, "gatewayapi_gateway_status", Labels{"type": "Programmed"}, Equal(0))
+	m.Series(r, "gatewayapi_gateway_created", gateway1, TimestampInPast())
+	m.Series(r, "gatewayapi_gatewayclass_info", Labels{"name": "testgatewayclass1", "namespace": ""}, Any())
+	m.Count(r, "gatewayapi_gateway_status", gateway1, 2)
+	m.Count(r, "gatewayapi_gateway_status", nil, 3)
+	m.None(r, "gatewayapi_gateway_status", gateway1.With(Labels{"type": "Conflicted"}))
+	m.None(r, "gatewayapi_httproute_created", nil)
+
+	if len(r.errors) != 0 {
+		t.Fatalf("unexpected errors:\n%s", strings.Join(r.errors, "\n"))
+	}
+}
+
+func TestFail(t *testing.T) {
+	m := parse(t)
+	gateway1 := Labels{"name": "testgateway1", "namespace": "default"}
+
+	for _, tc := range []struct {
+		name     string
+		assert   func(t testing.TB)
+		expected string
+	}{
+		{
+			name: "wrong value",
+			assert: func(t testing.TB) {
+				m.Series(t, "gatewayapi_gateway_status", Labels{"name": "testgateway2", "type": "Programmed"}, Equal(1))
+			},
+			expected: `expected a series gatewayapi_gateway_status{name="testgateway2",type="Programmed"} with 1, but none matched
+gatewayapi_gateway_status series:
+  gatewayapi_gateway_status{name="testgateway2",namespace="default",type="Programmed"} 0
+    value 0, expected 1
+  gatewayapi_gateway_status{name="testgateway1",namespace="default",type="Programmed"} 1
+    name="testgateway1", expected "testgateway2"
+  gatewayapi_gateway_status{name="testgateway1",namespace="default",type="Accepted"} 1
+    name="testgateway1", expected "testgateway2", type="Accepted", expected "Programmed"`,
+		},
+		{
+			name: "missing family",
+			assert: func(t testing.TB) {
+				m.Series(t, "gatewayapi_httproute_created", gateway1, TimestampInPast())
+			},
+			expected: `expected a series gatewayapi_httproute_created{name="testgateway1",namespace="default"} with a valid timestamp in the past, but none matched
+there are no gatewayapi_httproute_created series`,
+		},
+		{
+			name: "missing series",
+			assert: func(t testing.TB) {
+				m.Count(t, "gatewayapi_gateway_status", gateway1, 3)
+			},
+			expected: `expected 3 series matching gatewayapi_gateway_status{name="testgateway1",namespace="default"}, found 2:
+  gatewayapi_gateway_status{name="testgateway1",namespace="default",type="Programmed"} 1
+  gatewayapi_gateway_status{name="testgateway1",namespace="default",type="Accepted"} 1`,
+		},
+		{
+			name: "extra series",
+			assert: func(t testing.TB) {
+				m.None(t, "gatewayapi_gateway_status", Labels{"type": "Programmed"})
+			},
+			expected: `expected no series matching gatewayapi_gateway_status{type="Programmed"}, found 2:
+  gatewayapi_gateway_status{name="testgateway2",namespace="default",type="Programmed"} 0
+  gatewayapi_gateway_status{name="testgateway1",namespace="default",type="Programmed"} 1`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &recorder{TB: t}
+			tc.assert(r)
+			if len(r.errors) != 1 || r.errors[0] != tc.expected {
+				t.Fatalf("unexpected errors, got:\n%s\nexpected:\n%s", strings.Join(r.errors, "\n"), tc.expected)
+			}
+		})
+	}
+}
+
+func TestSelectAll(t *testing.T) {
+	m := parse(t)
+
+	var names []string
+	for _, s := range m.SelectAll(Labels{"name": "testgateway1"}) {
+		names = append(names, s.Name)
+	}
+	expected := "gatewayapi_gateway_created gatewayapi_gateway_status gatewayapi_gateway_status"
+	if actual := strings.Join(names, " "); actual != expected {
+		t.Fatalf("expected series %s, got %s", expected, actual)
+	}
+}
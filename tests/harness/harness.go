@@ -0,0 +1,302 @@
+// Package harness renders the metrics produced by a kube-state-metrics
+// CustomResourceState config for a set of Kubernetes objects, without a
+// cluster or a running kube-state-metrics instance.
+//
+// The config is compiled with kube-state-metrics' own customresourcestate
+// package and the objects are fed into the same metrics store and writer that
+// back the /metrics endpoint, so the returned text matches what a scrape of a
+// real instance would return for the same objects.
+package harness
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
+)
+
+// crd holds the parts of a CustomResourceDefinition the API server would use
+// when storing and serving an object.
+type crd struct {
+	namespaced bool
+	served     map[string]bool
+}
+
+// Harness holds a CustomResourceState config and the objects to render
+// metrics for.
+type Harness struct {
+	resources []customresourcestate.Resource
+	crds      map[schema.GroupKind]crd
+	objects   []*unstructured.Unstructured
+
+	// Now is used as the creationTimestamp of objects that don't set one,
+	// the same way the API server stamps objects on create.
+	Now time.Time
+}
+
+// New creates a Harness from the CustomResourceState config at configPath.
+func New(configPath string) (*Harness, error) {
+	f, err := os.Open(filepath.Clean(configPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open custom resource state config: %w", err)
+	}
+	defer f.Close()
+
+	return NewFromReader(f)
+}
+
+// NewFromReader creates a Harness from a CustomResourceState config.
+func NewFromReader(r io.Reader) (*Harness, error) {
+	var config customresourcestate.Metrics
+	// kube-state-metrics decodes --custom-resource-state-config-file with yaml.v3,
+	// so do the same here to get identical results for quirky yaml.
+	if err := yaml.NewDecoder(r).Decode(&config); err != nil {
+		return nil, fmt.Errorf("failed to parse custom resource state config: %w", err)
+	}
+
+	return &Harness{
+		resources: config.Spec.Resources,
+		crds:      map[schema.GroupKind]crd{},
+		Now:       time.Now().UTC().Truncate(time.Second),
+	}, nil
+}
+
+// LoadCRDs reads CustomResourceDefinitions from the given files or
+// directories. Known CRDs decide whether an object is namespaced and which
+// versions a resource in the config can be served at. Objects of a kind
+// without a CRD are treated as namespaced and only match their own version.
+func (h *Harness) LoadCRDs(paths ...string) error {
+	objs, err := readObjects(paths...)
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range objs {
+		if obj.GetKind() != "CustomResourceDefinition" {
+			continue
+		}
+		group, _, _ := unstructured.NestedString(obj.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(obj.Object, "spec", "names", "kind")
+		scope, _, _ := unstructured.NestedString(obj.Object, "spec", "scope")
+		versions, _, _ := unstructured.NestedSlice(obj.Object, "spec", "versions")
+
+		c := crd{namespaced: scope != "Cluster", served: map[string]bool{}}
+		for _, v := range versions {
+			version, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(version, "name")
+			served, _, _ := unstructured.NestedBool(version, "served")
+			c.served[name] = served
+		}
+		h.crds[schema.GroupKind{Group: group, Kind: kind}] = c
+	}
+	return nil
+}
+
+// LoadManifests reads Kubernetes objects, including their status, from the
+// given files or directories and adds them to the harness.
+func (h *Harness) LoadManifests(paths ...string) error {
+	objs, err := readObjects(paths...)
+	if err != nil {
+		return err
+	}
+	h.Add(objs...)
+	return nil
+}
+
+// Add adds objects to the harness. Fields the API server would set on create
+// (namespace, creationTimestamp, generation and uid) are defaulted if unset.
+func (h *Harness) Add(objs ...*unstructured.Unstructured) {
+	for _, obj := range objs {
+		obj = obj.DeepCopy()
+		gk := obj.GroupVersionKind().GroupKind()
+		if c, ok := h.crds[gk]; (!ok || c.namespaced) && obj.GetNamespace() == "" {
+			obj.SetNamespace(metav1.NamespaceDefault)
+		}
+		if created := obj.GetCreationTimestamp(); created.IsZero() {
+			obj.SetCreationTimestamp(metav1.NewTime(h.Now))
+		}
+		if obj.GetGeneration() == 0 {
+			obj.SetGeneration(1)
+		}
+		if obj.GetUID() == "" {
+			sum := sha256.Sum256([]byte(gk.String() + "/" + obj.GetNamespace() + "/" + obj.GetName()))
+			obj.SetUID(types.UID(fmt.Sprintf("%x", sum[:16])))
+		}
+		h.objects = append(h.objects, obj)
+	}
+}
+
+// Metrics writes the metrics for all objects in the harness to w, in the
+// kube-state-metrics text exposition format. Its signature matches
+// framework.KSMClient.Metrics so the two can be used interchangeably.
+func (h *Harness) Metrics(w io.Writer) error {
+	var writers metricsstore.MetricsWriterList
+	for _, resource := range h.resources {
+		factory, err := customresourcestate.NewCustomResourceMetrics(resource)
+		if err != nil {
+			return fmt.Errorf("failed to create metrics factory for %s: %w", resource.GroupVersionKind, err)
+		}
+		families := factory.MetricFamilyGenerators()
+		headers := generator.ExtractMetricFamilyHeaders(families)
+		generate := generator.ComposeMetricGenFuncs(families)
+
+		// Use a store per object, so objects are written in a stable order
+		// rather than the store's map order.
+		var stores []*metricsstore.MetricsStore
+		for _, obj := range h.served(resource.GroupVersionKind) {
+			store := metricsstore.NewMetricsStore(headers, generate)
+			if err := store.Add(obj); err != nil {
+				return fmt.Errorf("failed to add %s %s/%s: %w", obj.GetKind(), obj.GetNamespace(), obj.GetName(), err)
+			}
+			stores = append(stores, store)
+		}
+		if len(stores) == 0 {
+			stores = append(stores, metricsstore.NewMetricsStore(headers, generate))
+		}
+		writers = append(writers, metricsstore.NewMetricsWriter(stores...))
+	}
+
+	for _, writer := range metricsstore.SanitizeHeaders(writers) {
+		if err := writer.WriteAll(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// String returns the metrics for all objects in the harness.
+func (h *Harness) String() (string, error) {
+	buf := &bytes.Buffer{}
+	if err := h.Metrics(buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// served returns the objects that kube-state-metrics would list for gvk,
+// converted to that version, sorted by namespace and name.
+func (h *Harness) served(gvk customresourcestate.GroupVersionKind) []*unstructured.Unstructured {
+	want := schema.GroupVersionKind(gvk)
+	c, known := h.crds[want.GroupKind()]
+	if known && !c.served[want.Version] {
+		return nil
+	}
+
+	var objs []*unstructured.Unstructured
+	for _, obj := range h.objects {
+		have := obj.GroupVersionKind()
+		if have.GroupKind() != want.GroupKind() || (!known && have.Version != want.Version) {
+			continue
+		}
+		obj = obj.DeepCopy()
+		obj.SetGroupVersionKind(want)
+		objs = append(objs, obj)
+	}
+	sort.SliceStable(objs, func(i, j int) bool {
+		if objs[i].GetNamespace() != objs[j].GetNamespace() {
+			return objs[i].GetNamespace() < objs[j].GetNamespace()
+		}
+		return objs[i].GetName() < objs[j].GetName()
+	})
+	return objs
+}
+
+// readObjects decodes every yaml or json document in the given files, and in
+// the *.yaml, *.yml and *.json files of the given directories.
+func readObjects(paths ...string) ([]*unstructured.Unstructured, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			switch filepath.Ext(entry.Name()) {
+			case ".yaml", ".yml", ".json":
+				if !entry.IsDir() {
+					files = append(files, filepath.Join(path, entry.Name()))
+				}
+			}
+		}
+	}
+
+	var objs []*unstructured.Unstructured
+	for _, file := range files {
+		fileObjs, err := readFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		objs = append(objs, fileObjs...)
+	}
+	return objs, nil
+}
+
+func readFile(file string) ([]*unstructured.Unstructured, error) {
+	f, err := os.Open(filepath.Clean(file))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var objs []*unstructured.Unstructured
+	decoder := k8syaml.NewYAMLOrJSONDecoder(f, 4096)
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objs, nil
+			}
+			return nil, err
+		}
+		// Decode integers as int64 rather than float64, as the API server
+		// does, so fields such as metadata.generation can be read.
+		obj := map[string]interface{}{}
+		if err := utiljson.Unmarshal(raw, &obj); err != nil {
+			return nil, err
+		}
+		if len(obj) == 0 {
+			continue
+		}
+		u := &unstructured.Unstructured{Object: obj}
+		if strings.HasSuffix(u.GetKind(), "List") && u.IsList() {
+			if err := u.EachListItem(func(item runtime.Object) error {
+				objs = append(objs, item.(*unstructured.Unstructured))
+				return nil
+			}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		objs = append(objs, u)
+	}
+}
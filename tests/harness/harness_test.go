@@ -0,0 +1,107 @@
+package harness
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const testConfig = `kind: CustomResourceStateMetrics
+spec:
+  resources:
+    - groupVersionKind:
+        group: gateway.networking.k8s.io
+        kind: "GatewayClass"
+        version: "v1beta1"
+      metricNamePrefix: gatewayapi_gatewayclass
+      labelsFromPath:
+        name:
+        - metadata
+        - name
+      metrics:
+      - name: "status"
+        help: "status condition"
+        each:
+          type: Gauge
+          gauge:
+            path: [status, conditions]
+            labelsFromPath:
+              type: ["type"]
+            valueFrom: ["status"]
+    - groupVersionKind:
+        group: gateway.networking.k8s.io
+        kind: "HTTPRoute"
+        version: "v1beta1"
+      metricNamePrefix: gatewayapi_httproute
+      labelsFromPath:
+        name:
+        - metadata
+        - name
+        namespace:
+        - metadata
+        - namespace
+      metrics:
+      - name: "created"
+        help: "created timestamp"
+        each:
+          type: Gauge
+          gauge:
+            path: [metadata, creationTimestamp]
+`
+
+func TestMetrics(t *testing.T) {
+	h, err := NewFromReader(strings.NewReader(testConfig))
+	if err != nil {
+		t.Fatalf("failed to create harness: %v", err)
+	}
+	if err := h.LoadCRDs("../../config/gateway-api/crd/standard"); err != nil {
+		t.Fatalf("failed to load crds: %v", err)
+	}
+	if err := h.LoadManifests("../manifests/testgatewayclass1.yaml", "../manifests/testhttproute1.yaml"); err != nil {
+		t.Fatalf("failed to load manifests: %v", err)
+	}
+
+	actual, err := h.String()
+	if err != nil {
+		t.Fatalf("failed to render metrics: %v", err)
+	}
+
+	expected := []string{
+		"# HELP gatewayapi_gatewayclass_status status condition",
+		"# TYPE gatewayapi_gatewayclass_status gauge",
+		`gatewayapi_gatewayclass_status{customresource_group="gateway.networking.k8s.io",customresource_kind="GatewayClass",customresource_version="v1beta1",name="testgatewayclass1",type="Accepted"} 1`,
+		"# HELP gatewayapi_httproute_created created timestamp",
+		"# TYPE gatewayapi_httproute_created gauge",
+		// the API server sets the namespace and creationTimestamp on create
+		`gatewayapi_httproute_created{customresource_group="gateway.networking.k8s.io",customresource_kind="HTTPRoute",customresource_version="v1beta1",name="testroute1",namespace="default"} ` + strconv.FormatFloat(float64(h.Now.Unix()), 'g', -1, 64),
+		"",
+	}
+	if actual != strings.Join(expected, "\n") {
+		t.Fatalf("unexpected metrics, got:\n%s\nexpected:\n%s", actual, strings.Join(expected, "\n"))
+	}
+}
+
+func TestMetricsUnservedVersion(t *testing.T) {
+	config := strings.Replace(testConfig, `kind: "HTTPRoute"
+        version: "v1beta1"`, `kind: "HTTPRoute"
+        version: "v1alpha2"`, 1)
+	h, err := NewFromReader(strings.NewReader(config))
+	if err != nil {
+		t.Fatalf("failed to create harness: %v", err)
+	}
+	if err := h.LoadCRDs("../../config/gateway-api/crd/standard"); err != nil {
+		t.Fatalf("failed to load crds: %v", err)
+	}
+	if err := h.LoadManifests("../manifests/testhttproute1.yaml"); err != nil {
+		t.Fatalf("failed to load manifests: %v", err)
+	}
+
+	actual, err := h.String()
+	if err != nil {
+		t.Fatalf("failed to render metrics: %v", err)
+	}
+	// v1alpha2 HTTPRoutes are not served by the v0.8.0 CRDs, so only headers are written
+	if strings.Contains(actual, "gatewayapi_httproute_created{") {
+		t.Fatalf("expected no httproute series for an unserved version, got:\n%s", actual)
+	}
+}